@@ -4,6 +4,8 @@
 package main
 
 import (
+	"os"
+
 	"gitee.com/openeuler/uos-tc-exporter/internal/server"
 	"gitee.com/openeuler/uos-tc-exporter/pkg/errors"
 	"gitee.com/openeuler/uos-tc-exporter/pkg/logger"
@@ -12,6 +14,13 @@ import (
 
 func Run(name string, version string) error {
 	logger.InitDefaultLog()
+	switch server.ParseCommandLine(name) {
+	case server.DumpCommand:
+		return server.RunDump(os.Stdout)
+	case server.TopCommand:
+		return server.RunTop(os.Stdin, os.Stdout)
+	}
+
 	s := server.NewServer(name, version)
 
 	s.PrintVersion()
@@ -8,9 +8,15 @@ import (
 	"net"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
+	// 导入收集器包以注册默认配置，用于校验 collectors 配置段
+	_ "gitee.com/openeuler/uos-tc-exporter/internal/metrics/collectors/app"
+	_ "gitee.com/openeuler/uos-tc-exporter/internal/metrics/collectors/business"
+	_ "gitee.com/openeuler/uos-tc-exporter/internal/metrics/collectors/qdisc"
+	metricsconfig "gitee.com/openeuler/uos-tc-exporter/internal/metrics/config"
 	"gitee.com/openeuler/uos-tc-exporter/pkg/logger"
 	"gitee.com/openeuler/uos-tc-exporter/pkg/utils"
 	"github.com/alecthomas/kingpin"
@@ -39,12 +45,120 @@ type ServerConfig struct {
 	ShutdownTimeout time.Duration `yaml:"shutdownTimeout"` // 优雅关闭超时时间
 }
 
+// RateConfig 令牌桶限流配置，每个 Interval 补充一个令牌，最多累积 Burst 个
+// Burst 为 0 表示不限流
+type RateConfig struct {
+	Interval time.Duration `yaml:"interval"`
+	Burst    int           `yaml:"burst"`
+}
+
+// LimitsConfig HTTP 请求限制配置
+type LimitsConfig struct {
+	// PerClient 按客户端 IP 限流
+	PerClient RateConfig `yaml:"per_client"`
+	// MaxClients 按客户端限流时记录的最大客户端数，超过后淘汰最久未访问的客户端
+	MaxClients int `yaml:"max_clients"`
+	// Routes 按路由路径限流，对所有客户端共享
+	Routes map[string]RateConfig `yaml:"routes"`
+	// MaxInFlightScrapes 同时处理的指标抓取请求上限，0 表示不限制
+	MaxInFlightScrapes int `yaml:"max_in_flight_scrapes"`
+}
+
+// HealthConfig 健康检查配置
+type HealthConfig struct {
+	// CheckTimeout 单个检查器的超时时间，超时视为失败
+	CheckTimeout time.Duration `yaml:"check_timeout"`
+	// CacheTTL 检查结果的缓存时间，期间的探测直接返回缓存结果
+	CacheTTL time.Duration `yaml:"cache_ttl"`
+	// MaxConsecutiveFailures 连续失败多少次完整收集后指标检查失败
+	MaxConsecutiveFailures int `yaml:"max_consecutive_failures"`
+	// StaleAfter 收集持续失败或单次收集运行超过该时间后指标检查失败
+	StaleAfter time.Duration `yaml:"stale_after"`
+}
+
+// 监听地址支持的网络类型
+const (
+	ListenTCP     = "tcp"
+	ListenUnix    = "unix"
+	ListenSystemd = "systemd"
+)
+
+// ListenConfig 监听地址配置
+// Address 支持 host:port（或 tcp://host:port）、unix:///path/to.sock 和 systemd://[name]，
+// systemd:// 使用 systemd socket activation 传入的监听套接字，name 对应 LISTEN_FDNAMES（FileDescriptorName=）
+type ListenConfig struct {
+	Address string `yaml:"address"`
+	// SocketMode Unix 套接字文件的权限，八进制字符串，如 "0660"
+	SocketMode string `yaml:"socket_mode"`
+	// SocketOwner Unix 套接字文件的属主，用户名或 UID
+	SocketOwner string `yaml:"socket_owner"`
+	// SocketGroup Unix 套接字文件的属组，组名或 GID
+	SocketGroup string `yaml:"socket_group"`
+}
+
+// Parse 解析监听地址，返回网络类型（tcp、unix 或 systemd）和地址
+func (l ListenConfig) Parse() (network, address string, err error) {
+	network, address, found := strings.Cut(l.Address, "://")
+	if !found {
+		network, address = ListenTCP, l.Address
+	}
+	switch network {
+	case ListenTCP:
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			return "", "", fmt.Errorf("invalid tcp address %q: %w", l.Address, err)
+		}
+	case ListenUnix:
+		if !strings.HasPrefix(address, "/") {
+			return "", "", fmt.Errorf("unix socket path must be absolute, got %q", l.Address)
+		}
+	case ListenSystemd:
+	default:
+		return "", "", fmt.Errorf("unsupported listen address scheme %q in %q", network, l.Address)
+	}
+	if network != ListenUnix && (l.SocketMode != "" || l.SocketOwner != "" || l.SocketGroup != "") {
+		return "", "", fmt.Errorf("socket_mode, socket_owner and socket_group only apply to unix sockets, got %q", l.Address)
+	}
+	return network, address, nil
+}
+
+// FileMode 返回 Unix 套接字文件的权限，未配置时返回 0
+func (l ListenConfig) FileMode() (os.FileMode, error) {
+	if l.SocketMode == "" {
+		return 0, nil
+	}
+	mode, err := strconv.ParseUint(l.SocketMode, 8, 32)
+	if err != nil || mode > 0o777 {
+		return 0, fmt.Errorf("invalid socket_mode %q, must be an octal permission such as 0660", l.SocketMode)
+	}
+	return os.FileMode(mode), nil
+}
+
 type Config struct {
 	Logging     logger.Config `yaml:"log"`
 	Address     string        `yaml:"address" validate:"required,ip|hostname|interface"`
 	Port        int           `yaml:"port" validate:"required,min=1,max=65535"`
 	MetricsPath string        `yaml:"metricsPath" validate:"required,startswith=/"`
 	Server      ServerConfig  `yaml:"server"`
+	// Listen 监听地址列表，配置后替代 address/port，所有地址共享同一套路由
+	Listen []ListenConfig `yaml:"listen"`
+	// WebConfigFile TLS 与认证配置文件路径，格式与 exporter-toolkit 的 web-config.yml 兼容
+	WebConfigFile string `yaml:"web_config_file"`
+	// Limits 按客户端、路由的限流和并发抓取上限
+	Limits LimitsConfig `yaml:"limits"`
+	// Sampler 高频队列采样配置
+	Sampler metricsconfig.SamplerConfig `yaml:"sampler"`
+	// Monitoring 指标管理器配置
+	Monitoring metricsconfig.ManagerConfig `yaml:"monitoring"`
+	// Collectors 按收集器 ID 覆盖启用状态、超时、重试次数和指标配置
+	Collectors metricsconfig.CollectorsConfig `yaml:"collectors"`
+	// Health /health 与 /ready 的检查配置
+	Health HealthConfig `yaml:"health"`
+	// Push 定期推送到 Pushgateway
+	Push PushConfig `yaml:"push"`
+	// RemoteWrite 以 Prometheus remote_write 协议发送，发送失败的样本暂存在磁盘 WAL 中
+	RemoteWrite RemoteWriteConfig `yaml:"remote_write"`
+	// OTLP 以 OTLP/HTTP 导出指标，可以替代 Prometheus 指标端点
+	OTLP OTLPConfig `yaml:"otlp"`
 }
 
 var (
@@ -61,6 +175,22 @@ var (
 		Server: ServerConfig{
 			ShutdownTimeout: 30 * time.Second, // 默认30秒关闭超时
 		},
+		Limits: LimitsConfig{
+			MaxClients: 1024,
+		},
+		Health: HealthConfig{
+			CheckTimeout:           2 * time.Second,
+			CacheTTL:               5 * time.Second,
+			MaxConsecutiveFailures: 3,
+			StaleAfter:             5 * time.Minute,
+		},
+		Monitoring: metricsconfig.ManagerConfig{
+			Enabled:               true,
+			PerformanceMonitoring: true,
+			CollectionInterval:    30 * time.Second,
+			StatsRetention:        24 * time.Hour,
+			EnableBusinessMetrics: true,
+		},
 	}
 )
 
@@ -94,12 +224,32 @@ func (c *Config) Validate() error {
 	var errors []string
 
 	validate := validator.New()
+	// 注册 interface 校验，允许将网络接口名作为监听地址
+	validate.RegisterValidation("interface", func(fl validator.FieldLevel) bool {
+		return c.isValidInterface(fl.Field().String())
+	})
 	if err := validate.Struct(c); err != nil {
 		for _, err := range err.(validator.ValidationErrors) {
 			errors = append(errors, fmt.Sprintf("field '%s' failed validation tag '%s'", err.Field(), err.Tag()))
 		}
 	}
 
+	// 验证监听地址、端口和指标路径
+	if err := c.validateAddress(); err != nil {
+		errors = append(errors, fmt.Sprintf("address validation failed: %v", err))
+	}
+	if err := c.validatePort(); err != nil {
+		errors = append(errors, fmt.Sprintf("port validation failed: %v", err))
+	}
+	if err := c.validateMetricsPath(); err != nil {
+		errors = append(errors, fmt.Sprintf("metrics path validation failed: %v", err))
+	}
+
+	// 验证监听地址列表
+	if err := c.validateListen(); err != nil {
+		errors = append(errors, fmt.Sprintf("listen validation failed: %v", err))
+	}
+
 	// 验证日志配置
 	if err := c.validateLogging(); err != nil {
 		errors = append(errors, fmt.Sprintf("logging validation failed: %v", err))
@@ -110,6 +260,37 @@ func (c *Config) Validate() error {
 		errors = append(errors, fmt.Sprintf("server validation failed: %v", err))
 	}
 
+	// 验证限流配置
+	if err := c.validateLimits(); err != nil {
+		errors = append(errors, fmt.Sprintf("limits validation failed: %v", err))
+	}
+
+	// 验证健康检查配置
+	if err := c.validateHealth(); err != nil {
+		errors = append(errors, fmt.Sprintf("health validation failed: %v", err))
+	}
+
+	// 验证推送配置
+	if err := c.validatePush(); err != nil {
+		errors = append(errors, fmt.Sprintf("push validation failed: %v", err))
+	}
+	if err := c.validateRemoteWrite(); err != nil {
+		errors = append(errors, fmt.Sprintf("remote_write validation failed: %v", err))
+	}
+	if err := c.validateOTLP(); err != nil {
+		errors = append(errors, fmt.Sprintf("otlp validation failed: %v", err))
+	}
+
+	// 验证采样配置
+	if err := c.Sampler.Validate(); err != nil {
+		errors = append(errors, fmt.Sprintf("sampler validation failed: %v", err))
+	}
+
+	// 验证收集器配置
+	if err := c.Collectors.Validate(); err != nil {
+		errors = append(errors, fmt.Sprintf("collectors validation failed: %v", err))
+	}
+
 	if len(errors) > 0 {
 		return fmt.Errorf("configuration validation failed:\n%s", strings.Join(errors, "\n"))
 	}
@@ -244,6 +425,14 @@ func (c *Config) GetBindAddress() string {
 	return fmt.Sprintf("%s:%d", c.Address, c.Port)
 }
 
+// ListenAddresses 返回生效的监听地址，未配置 listen 时为 address:port
+func (c *Config) ListenAddresses() []ListenConfig {
+	if len(c.Listen) == 0 {
+		return []ListenConfig{{Address: c.GetBindAddress()}}
+	}
+	return c.Listen
+}
+
 // IsLocalhost 检查是否为本地地址
 func (c *Config) IsLocalhost() bool {
 	return c.Address == "127.0.0.1" || c.Address == "localhost" || c.Address == "::1"
@@ -286,6 +475,80 @@ func (c *Config) validateServer() error {
 	return nil
 }
 
+// validateListen 验证监听地址列表
+func (c *Config) validateListen() error {
+	seen := make(map[string]bool, len(c.Listen))
+	for _, l := range c.Listen {
+		network, address, err := l.Parse()
+		if err != nil {
+			return err
+		}
+		if _, err := l.FileMode(); err != nil {
+			return err
+		}
+		key := network + "://" + address
+		if seen[key] {
+			return fmt.Errorf("duplicate listen address %q", l.Address)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// validateLimits 验证限流配置
+func (c *Config) validateLimits() error {
+	if err := c.Limits.PerClient.validate(); err != nil {
+		return fmt.Errorf("per_client: %w", err)
+	}
+	if c.Limits.PerClient.Burst > 0 && c.Limits.MaxClients <= 0 {
+		return fmt.Errorf("max_clients must be positive when per_client limit is enabled, got %d", c.Limits.MaxClients)
+	}
+	for route, rate := range c.Limits.Routes {
+		if !strings.HasPrefix(route, "/") {
+			return fmt.Errorf("route %q must start with '/'", route)
+		}
+		if err := rate.validate(); err != nil {
+			return fmt.Errorf("route %s: %w", route, err)
+		}
+	}
+	if c.Limits.MaxInFlightScrapes < 0 {
+		return fmt.Errorf("max_in_flight_scrapes cannot be negative, got %d", c.Limits.MaxInFlightScrapes)
+	}
+	return nil
+}
+
+// validateHealth 验证健康检查配置，未设置的项使用默认值
+func (c *Config) validateHealth() error {
+	h, def := &c.Health, DefaultConfig.Health
+	if h.CheckTimeout < 0 || h.CacheTTL < 0 || h.StaleAfter < 0 || h.MaxConsecutiveFailures < 0 {
+		return fmt.Errorf("check_timeout, cache_ttl, stale_after and max_consecutive_failures cannot be negative")
+	}
+	if h.CheckTimeout == 0 {
+		h.CheckTimeout = def.CheckTimeout
+	}
+	if h.CacheTTL == 0 {
+		h.CacheTTL = def.CacheTTL
+	}
+	if h.MaxConsecutiveFailures == 0 {
+		h.MaxConsecutiveFailures = def.MaxConsecutiveFailures
+	}
+	if h.StaleAfter == 0 {
+		h.StaleAfter = def.StaleAfter
+	}
+	return nil
+}
+
+// validate 验证令牌桶参数
+func (rc RateConfig) validate() error {
+	if rc.Burst < 0 {
+		return fmt.Errorf("burst cannot be negative, got %d", rc.Burst)
+	}
+	if rc.Burst > 0 && rc.Interval <= 0 {
+		return fmt.Errorf("interval must be positive when burst is set, got %v", rc.Interval)
+	}
+	return nil
+}
+
 // validateLogging 验证日志配置
 func (c *Config) validateLogging() error {
 	// 验证日志级别
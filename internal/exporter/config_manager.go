@@ -5,6 +5,8 @@ package exporter
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"os"
@@ -19,16 +21,22 @@ import (
 
 // ConfigManager 配置管理器，负责配置的加载、验证和热重载
 type ConfigManager struct {
-	configPath  string
-	config      *Config
-	watcher     *fsnotify.Watcher
-	reloadChan  chan struct{}
-	stopChan    chan struct{}
-	mu          sync.RWMutex
+	configPath string
+	config     *Config
+	watcher    *fsnotify.Watcher
+	reloadChan chan struct{}
+	stopChan   chan struct{}
+	mu         sync.RWMutex
+	// reloadMu 串行化 Reload，避免并发重载的回调交错执行
+	reloadMu    sync.Mutex
 	reloadDelay time.Duration
 	onReload    func(*Config) error
 	lastReload  time.Time
 	reloadCount int
+	// configHash 当前生效配置文件内容的 sha256
+	configHash string
+	// persistMu 串行化对配置文件的写回
+	persistMu sync.Mutex
 }
 
 // NewConfigManager 创建新的配置管理器
@@ -54,31 +62,32 @@ func NewConfigManager(configPath string) (*ConfigManager, error) {
 	return cm, nil
 }
 
-// loadConfigFromFile 从文件加载配置（内部辅助方法）
-func (cm *ConfigManager) loadConfigFromFile() (*Config, error) {
+// loadConfigFromFile 从文件加载配置（内部辅助方法），同时返回文件内容的哈希
+func (cm *ConfigManager) loadConfigFromFile() (*Config, string, error) {
 	// 检查文件是否存在
 	if !cm.fileExists() {
-		return nil, errors.New("config file not found")
+		return nil, "", errors.New("config file not found")
 	}
 
 	// 读取配置文件
 	content, err := os.ReadFile(cm.configPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, "", fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	// 解析YAML
-	var newConfig Config
+	// 解析YAML，未出现在文件中的字段沿用默认值
+	newConfig := DefaultConfig
 	if err := yaml.Unmarshal(content, &newConfig); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
+		return nil, "", fmt.Errorf("failed to parse config file: %w", err)
 	}
 
 	// 验证配置
 	if err := newConfig.Validate(); err != nil {
-		return nil, fmt.Errorf("config validation failed: %w", err)
+		return nil, "", fmt.Errorf("config validation failed: %w", err)
 	}
 
-	return &newConfig, nil
+	sum := sha256.Sum256(content)
+	return &newConfig, hex.EncodeToString(sum[:]), nil
 }
 
 // LoadConfig 加载配置文件
@@ -86,7 +95,7 @@ func (cm *ConfigManager) LoadConfig() error {
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
 
-	newConfig, err := cm.loadConfigFromFile()
+	newConfig, hash, err := cm.loadConfigFromFile()
 	if err != nil {
 		logrus.Warnf("Config file %s not found", cm.configPath)
 		return err
@@ -94,41 +103,46 @@ func (cm *ConfigManager) LoadConfig() error {
 
 	// 应用配置
 	cm.config = newConfig
+	cm.configHash = hash
 	logrus.Debugf("Config loaded: address=%s, port=%d, metricsPath=%s", cm.config.Address, cm.config.Port, cm.config.MetricsPath)
 	return nil
 }
 
 // Reload 重新加载配置文件
+// 配置在 mu 之外加载和校验，回调期间其他协程仍可读取旧配置，回调成功后才切换到新配置
 func (cm *ConfigManager) Reload() error {
-	cm.mu.Lock()
-	defer cm.mu.Unlock()
+	cm.reloadMu.Lock()
+	defer cm.reloadMu.Unlock()
 
-	newConfig, err := cm.loadConfigFromFile()
+	newConfig, hash, err := cm.loadConfigFromFile()
 	if err != nil {
 		logrus.Warnf("Config file %s not found during reload: %v", cm.configPath, err)
 		return err
 	}
 
-	// 保存旧配置用于回滚
-	oldConfig := cm.config
+	cm.mu.RLock()
+	onReload := cm.onReload
+	cm.mu.RUnlock()
+
+	// 调用重载回调（如果设置），失败时保留旧配置
+	if onReload != nil {
+		if err := onReload(newConfig); err != nil {
+			logrus.Errorf("Config reload callback failed: %v", err)
+			return err
+		}
+	}
 
 	// 应用新配置
+	cm.mu.Lock()
 	cm.config = newConfig
+	cm.configHash = hash
 	cm.lastReload = time.Now()
 	cm.reloadCount++
+	reloadCount := cm.reloadCount
+	cm.mu.Unlock()
 
-	logrus.Infof("Config reloaded successfully from %s (reload count: %d)", cm.configPath, cm.reloadCount)
-	logrus.Debugf("Config reloaded: address=%s, port=%d, metricsPath=%s", cm.config.Address, cm.config.Port, cm.config.MetricsPath)
-
-	// 调用重载回调（如果设置）
-	if cm.onReload != nil {
-		if err := cm.onReload(cm.config); err != nil {
-			logrus.Errorf("Config reload callback failed: %v", err)
-			// 回滚到旧配置
-			cm.config = oldConfig
-			return err
-		}
-	}
+	logrus.Infof("Config reloaded successfully from %s (reload count: %d)", cm.configPath, reloadCount)
+	logrus.Debugf("Config reloaded: address=%s, port=%d, metricsPath=%s", newConfig.Address, newConfig.Port, newConfig.MetricsPath)
 
 	return nil
 }
@@ -246,5 +260,6 @@ func (cm *ConfigManager) GetStats() map[string]any {
 		"reload_count": cm.reloadCount,
 		"is_watching":  cm.watcher != nil,
 		"reload_delay": cm.reloadDelay,
+		"config_hash":  cm.configHash,
 	}
 }
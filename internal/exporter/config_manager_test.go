@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigManagerReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tc-exporter.yaml")
+	if err := os.WriteFile(path, []byte("port: 9070\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cm, err := NewConfigManager(path)
+	if err != nil {
+		t.Fatalf("NewConfigManager() error = %v", err)
+	}
+	defer cm.watcher.Close()
+	if err := cm.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	// 回调运行时不持有锁，可以读取当前（旧）配置和统计信息
+	var seenPort int
+	cm.SetReloadCallback(func(cfg *Config) error {
+		seenPort = cm.GetConfig().Port
+		_ = cm.GetStats()
+		if cfg.Port == 9072 {
+			return errors.New("rejected")
+		}
+		return nil
+	})
+
+	if err := os.WriteFile(path, []byte("port: 9071\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := cm.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if seenPort != 9070 {
+		t.Errorf("callback saw port %d, want the old config 9070", seenPort)
+	}
+	if port := cm.GetConfig().Port; port != 9071 {
+		t.Errorf("port after reload = %d, want 9071", port)
+	}
+
+	// 回调失败时保留旧配置
+	if err := os.WriteFile(path, []byte("port: 9072\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := cm.Reload(); err == nil {
+		t.Fatal("Reload() error = nil, want the callback error")
+	}
+	if port := cm.GetConfig().Port; port != 9071 {
+		t.Errorf("port after failed reload = %d, want 9071", port)
+	}
+	if count := cm.GetStats()["reload_count"]; count != 1 {
+		t.Errorf("reload_count = %v, want 1", count)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	metricsconfig "gitee.com/openeuler/uos-tc-exporter/internal/metrics/config"
 	"gitee.com/openeuler/uos-tc-exporter/pkg/logger"
 )
 
@@ -145,6 +146,44 @@ func TestConfig_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "metrics path validation failed",
 		},
+		{
+			name: "unknown collector",
+			config: Config{
+				Logging: logger.Config{
+					Level:   "info",
+					LogPath: "/var/log/test.log",
+					MaxSize: "10MB",
+					MaxAge:  time.Hour * 24,
+				},
+				Address:     "127.0.0.1",
+				Port:        9062,
+				MetricsPath: "/metrics",
+				Collectors: metricsconfig.CollectorsConfig{
+					"qdisc_unknown": {},
+				},
+			},
+			wantErr: true,
+			errMsg:  "collectors validation failed",
+		},
+		{
+			name: "unknown collector metric",
+			config: Config{
+				Logging: logger.Config{
+					Level:   "info",
+					LogPath: "/var/log/test.log",
+					MaxSize: "10MB",
+					MaxAge:  time.Hour * 24,
+				},
+				Address:     "127.0.0.1",
+				Port:        9062,
+				MetricsPath: "/metrics",
+				Collectors: metricsconfig.CollectorsConfig{
+					"qdisc_codel": {Metrics: map[string]metricsconfig.MetricSettings{"unknown": {}}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "unknown metric",
+		},
 	}
 
 	for _, tt := range tests {
@@ -230,6 +269,132 @@ func TestConfig_validatePort(t *testing.T) {
 	}
 }
 
+func TestConfig_validateListen(t *testing.T) {
+	tests := []struct {
+		name    string
+		listen  []ListenConfig
+		wantErr bool
+	}{
+		{"empty", nil, false},
+		{"tcp and unix", []ListenConfig{
+			{Address: "127.0.0.1:9062"},
+			{Address: "unix:///run/tc-exporter.sock", SocketMode: "0660", SocketGroup: "prometheus"},
+		}, false},
+		{"systemd", []ListenConfig{{Address: "systemd://"}, {Address: "systemd://metrics"}}, false},
+		{"tcp without port", []ListenConfig{{Address: "127.0.0.1"}}, true},
+		{"relative unix path", []ListenConfig{{Address: "unix://tc.sock"}}, true},
+		{"unknown scheme", []ListenConfig{{Address: "udp://127.0.0.1:9062"}}, true},
+		{"invalid mode", []ListenConfig{{Address: "unix:///run/tc.sock", SocketMode: "0999"}}, true},
+		{"mode on tcp", []ListenConfig{{Address: "tcp://127.0.0.1:9062", SocketMode: "0660"}}, true},
+		{"duplicate", []ListenConfig{{Address: "127.0.0.1:9062"}, {Address: "tcp://127.0.0.1:9062"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{Listen: tt.listen}
+			err := config.validateListen()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateListen() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_validatePush(t *testing.T) {
+	tests := []struct {
+		name    string
+		push    PushConfig
+		wantErr bool
+	}{
+		{"disabled", PushConfig{URL: "not a url"}, false},
+		{"defaults", PushConfig{Enabled: true, URL: "http://pushgateway:9091"}, false},
+		{"missing url", PushConfig{Enabled: true}, true},
+		{"relative url", PushConfig{Enabled: true, URL: "pushgateway:9091"}, true},
+		{"invalid method", PushConfig{Enabled: true, URL: "http://pushgateway:9091", Method: "patch"}, true},
+		{"invalid grouping", PushConfig{Enabled: true, URL: "http://pushgateway:9091", Grouping: map[string]string{"job": "x"}}, true},
+		{"basic auth without user", PushConfig{Enabled: true, URL: "http://pushgateway:9091", BasicAuth: &BasicAuthConfig{Password: "x"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{Push: tt.push}
+			err := config.validatePush()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validatePush() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && tt.push.Enabled && (config.Push.Interval != DefaultPushConfig.Interval || config.Push.Method != PushMethodPut) {
+				t.Errorf("validatePush() did not fill defaults: %+v", config.Push)
+			}
+		})
+	}
+}
+
+func TestConfig_validateRemoteWrite(t *testing.T) {
+	const url = "http://prometheus:9090/api/v1/write"
+	tests := []struct {
+		name    string
+		rw      RemoteWriteConfig
+		wantErr bool
+	}{
+		{"disabled", RemoteWriteConfig{URL: "not a url"}, false},
+		{"defaults", RemoteWriteConfig{Enabled: true, URL: url}, false},
+		{"missing url", RemoteWriteConfig{Enabled: true}, true},
+		{"relative wal dir", RemoteWriteConfig{Enabled: true, URL: url, WALDir: "wal"}, true},
+		{"invalid wal size", RemoteWriteConfig{Enabled: true, URL: url, WALMaxSize: "lots"}, true},
+		{"wal size too small", RemoteWriteConfig{Enabled: true, URL: url, WALMaxSize: "100KB"}, true},
+		{"invalid external label", RemoteWriteConfig{Enabled: true, URL: url, ExternalLabels: map[string]string{"__name__": "x"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{RemoteWrite: tt.rw}
+			err := config.validateRemoteWrite()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateRemoteWrite() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && tt.rw.Enabled && (config.RemoteWrite.Interval != DefaultRemoteWriteConfig.Interval || config.RemoteWrite.WALDir != DefaultRemoteWriteConfig.WALDir) {
+				t.Errorf("validateRemoteWrite() did not fill defaults: %+v", config.RemoteWrite)
+			}
+		})
+	}
+}
+
+func TestConfig_validateOTLP(t *testing.T) {
+	const endpoint = "http://otel-collector:4318/v1/metrics"
+	tests := []struct {
+		name       string
+		otlp       OTLPConfig
+		wantErr    bool
+		scrapePath string
+	}{
+		{"disabled", OTLPConfig{Endpoint: "not a url", DisableMetricsEndpoint: true}, false, "/metrics"},
+		{"defaults", OTLPConfig{Enabled: true, Endpoint: endpoint}, false, "/metrics"},
+		{"otlp only", OTLPConfig{Enabled: true, Endpoint: endpoint, DisableMetricsEndpoint: true}, false, ""},
+		{"missing endpoint", OTLPConfig{Enabled: true}, true, ""},
+		{"invalid compression", OTLPConfig{Enabled: true, Endpoint: endpoint, Compression: "zstd"}, true, ""},
+		{"reserved header", OTLPConfig{Enabled: true, Endpoint: endpoint, Headers: map[string]Secret{"content-type": "x"}}, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{MetricsPath: "/metrics", OTLP: tt.otlp}
+			err := config.validateOTLP()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateOTLP() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := config.ScrapePath(); got != tt.scrapePath {
+				t.Errorf("ScrapePath() = %q, want %q", got, tt.scrapePath)
+			}
+			if tt.otlp.Enabled && (config.OTLP.Interval != DefaultOTLPConfig.Interval || config.OTLP.Compression != OTLPCompressionGzip) {
+				t.Errorf("validateOTLP() did not fill defaults: %+v", config.OTLP)
+			}
+		})
+	}
+}
+
 func TestConfig_validateMetricsPath(t *testing.T) {
 	tests := []struct {
 		name    string
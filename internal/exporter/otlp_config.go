@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OTLP/HTTP 请求体的压缩方式
+const (
+	OTLPCompressionGzip = "gzip"
+	OTLPCompressionNone = "none"
+)
+
+// OTLPConfig OTLP/HTTP 指标导出配置
+type OTLPConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Endpoint OTLP/HTTP 指标接收地址，如 http://otel-collector:4318/v1/metrics
+	Endpoint string `yaml:"endpoint"`
+	// Interval 导出间隔
+	Interval time.Duration `yaml:"interval"`
+	// Timeout 单次导出的超时时间
+	Timeout time.Duration `yaml:"timeout"`
+	// Compression 请求体压缩方式，gzip 或 none
+	Compression string `yaml:"compression"`
+	// Headers 附加的请求头，如认证令牌
+	Headers map[string]Secret `yaml:"headers"`
+	// ResourceAttributes 附加或覆盖的资源属性，默认包含 service.*、host.* 和 os.type
+	ResourceAttributes map[string]string `yaml:"resource_attributes"`
+	// MaxRetries 可重试的失败（网络错误、429、502、503、504）的重试次数，间隔从 RetryBackoff 开始按指数增长，不超过 Interval
+	MaxRetries   int           `yaml:"max_retries"`
+	RetryBackoff time.Duration `yaml:"retry_backoff"`
+	// TLS 客户端 TLS 配置
+	TLS *ClientTLSConfig `yaml:"tls"`
+	// DisableMetricsEndpoint 只通过 OTLP 导出，不再提供 Prometheus 指标端点，健康检查等其余路由不受影响
+	DisableMetricsEndpoint bool `yaml:"disable_metrics_endpoint"`
+}
+
+// DefaultOTLPConfig OTLP 配置的默认值
+var DefaultOTLPConfig = OTLPConfig{
+	Interval:     30 * time.Second,
+	Timeout:      10 * time.Second,
+	Compression:  OTLPCompressionGzip,
+	MaxRetries:   3,
+	RetryBackoff: time.Second,
+}
+
+// ScrapePath 返回 Prometheus 指标端点的路径，指标只通过 OTLP 导出时返回空字符串
+func (c *Config) ScrapePath() string {
+	if c.OTLP.Enabled && c.OTLP.DisableMetricsEndpoint {
+		return ""
+	}
+	return c.MetricsPath
+}
+
+// validateOTLP 验证 OTLP 配置，未设置的项使用默认值
+func (c *Config) validateOTLP() error {
+	o, def := &c.OTLP, DefaultOTLPConfig
+	if !o.Enabled {
+		return nil
+	}
+	if err := validateHTTPURL(o.Endpoint); err != nil {
+		return err
+	}
+	if o.Interval < 0 || o.Timeout < 0 || o.RetryBackoff < 0 || o.MaxRetries < 0 {
+		return fmt.Errorf("interval, timeout, retry_backoff and max_retries cannot be negative")
+	}
+	if o.Interval == 0 {
+		o.Interval = def.Interval
+	}
+	if o.Timeout == 0 {
+		o.Timeout = def.Timeout
+	}
+	if o.MaxRetries == 0 {
+		o.MaxRetries = def.MaxRetries
+	}
+	if o.RetryBackoff == 0 {
+		o.RetryBackoff = def.RetryBackoff
+	}
+	if o.Compression == "" {
+		o.Compression = def.Compression
+	}
+	o.Compression = strings.ToLower(o.Compression)
+	if o.Compression != OTLPCompressionGzip && o.Compression != OTLPCompressionNone {
+		return fmt.Errorf("compression must be gzip or none, got %q", o.Compression)
+	}
+	for name := range o.Headers {
+		switch http.CanonicalHeaderKey(name) {
+		case "Content-Type", "Content-Encoding", "Content-Length":
+			return fmt.Errorf("header %q is set by the exporter and cannot be overridden", name)
+		}
+	}
+	for key := range o.ResourceAttributes {
+		if key == "" {
+			return fmt.Errorf("resource attribute key cannot be empty")
+		}
+	}
+	if _, err := o.TLS.Build(); err != nil {
+		return err
+	}
+	return nil
+}
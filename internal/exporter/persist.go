@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	metricsconfig "gitee.com/openeuler/uos-tc-exporter/internal/metrics/config"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// PersistCollectorSettings 将收集器配置写回配置文件的 collectors 段并立即重载
+// 通过 yaml.Node 修改，保留文件中的注释和其余内容；新配置校验失败或重载失败时恢复原文件
+func (cm *ConfigManager) PersistCollectorSettings(id string, settings metricsconfig.CollectorSettings) error {
+	cm.persistMu.Lock()
+	defer cm.persistMu.Unlock()
+
+	original, err := os.ReadFile(cm.configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	updated, err := setCollectorSettings(original, id, settings)
+	if err != nil {
+		return err
+	}
+
+	// 写入前按加载时的规则校验，避免写入无法加载的配置
+	newConfig := DefaultConfig
+	if err := yaml.Unmarshal(updated, &newConfig); err != nil {
+		return fmt.Errorf("failed to parse updated config: %w", err)
+	}
+	if err := newConfig.Validate(); err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+
+	if err := writeFileAtomic(cm.configPath, updated); err != nil {
+		return err
+	}
+	if err := cm.Reload(); err != nil {
+		if restoreErr := writeFileAtomic(cm.configPath, original); restoreErr != nil {
+			logrus.Errorf("Failed to restore config file %s: %v", cm.configPath, restoreErr)
+		}
+		return fmt.Errorf("failed to apply persisted config: %w", err)
+	}
+	logrus.Infof("Collector %s settings persisted to %s", id, cm.configPath)
+	return nil
+}
+
+// setCollectorSettings 返回把 collectors.<id> 替换为 settings 后的 YAML 内容
+func setCollectorSettings(content []byte, id string, settings metricsconfig.CollectorSettings) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("config file root is not a mapping")
+	}
+
+	collectors := mappingValue(root, "collectors")
+	if collectors.Kind != yaml.MappingNode {
+		// collectors 为空值时替换为映射
+		*collectors = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	}
+	var value yaml.Node
+	if err := value.Encode(settings); err != nil {
+		return nil, fmt.Errorf("failed to encode collector settings: %w", err)
+	}
+	*mappingValue(collectors, id) = value
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to encode config file: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// mappingValue 返回映射中 key 对应的值节点，不存在时追加一个空值
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null"}
+	mapping.Content = append(mapping.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	return value
+}
+
+// writeFileAtomic 先写入同目录的临时文件再重命名，保留原文件权限
+func writeFileAtomic(path string, data []byte) error {
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to chmod temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace config file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	metricsconfig "gitee.com/openeuler/uos-tc-exporter/internal/metrics/config"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSetCollectorSettings(t *testing.T) {
+	content := []byte(`# exporter config
+port: 9062
+collectors:
+  qdisc_codel:
+    enabled: true # keep codel
+  qdisc_cbq:
+    retries: 1
+`)
+	disabled, retries := false, 2
+	updated, err := setCollectorSettings(content, "qdisc_codel", metricsconfig.CollectorSettings{
+		Enabled: &disabled,
+		Timeout: 5 * time.Second,
+		Retries: &retries,
+	})
+	if err != nil {
+		t.Fatalf("setCollectorSettings() error = %v", err)
+	}
+	if !strings.Contains(string(updated), "# exporter config") {
+		t.Errorf("comments not preserved:\n%s", updated)
+	}
+
+	var cfg struct {
+		Port       int                            `yaml:"port"`
+		Collectors metricsconfig.CollectorsConfig `yaml:"collectors"`
+	}
+	if err := yaml.Unmarshal(updated, &cfg); err != nil {
+		t.Fatalf("Unmarshal() error = %v\n%s", err, updated)
+	}
+	codel := cfg.Collectors["qdisc_codel"]
+	if cfg.Port != 9062 || codel.Enabled == nil || *codel.Enabled ||
+		codel.Timeout != 5*time.Second || codel.Retries == nil || *codel.Retries != 2 {
+		t.Errorf("unexpected config after update:\n%s", updated)
+	}
+	if cbq := cfg.Collectors["qdisc_cbq"]; cbq.Retries == nil || *cbq.Retries != 1 {
+		t.Errorf("other collectors changed:\n%s", updated)
+	}
+
+	// 没有 collectors 段时新建
+	updated, err = setCollectorSettings([]byte("port: 9062\n"), "qdisc_cbq", metricsconfig.CollectorSettings{Enabled: &disabled})
+	if err != nil {
+		t.Fatalf("setCollectorSettings() error = %v", err)
+	}
+	if err := yaml.Unmarshal(updated, &cfg); err != nil {
+		t.Fatalf("Unmarshal() error = %v\n%s", err, updated)
+	}
+	if cbq := cfg.Collectors["qdisc_cbq"]; cbq.Enabled == nil || *cbq.Enabled {
+		t.Errorf("collectors section not created:\n%s", updated)
+	}
+}
@@ -0,0 +1,202 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// labelNameRegex Prometheus 标签名的格式
+var labelNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// 推送到 Pushgateway 的 HTTP 方法
+const (
+	// PushMethodPut 替换分组内的全部指标
+	PushMethodPut = "put"
+	// PushMethodPost 只替换同名指标
+	PushMethodPost = "post"
+)
+
+// BasicAuthConfig 客户端 HTTP 基本认证，Password 和 PasswordFile 二选一
+type BasicAuthConfig struct {
+	Username     string `yaml:"username"`
+	Password     Secret `yaml:"password"`
+	PasswordFile string `yaml:"password_file"`
+}
+
+// ClientTLSConfig 客户端 TLS 配置
+type ClientTLSConfig struct {
+	// CAFile 校验服务端证书的 CA，为空时使用系统 CA
+	CAFile string `yaml:"ca_file"`
+	// CertFile/KeyFile 客户端证书，服务端要求双向认证时配置
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+	// ServerName 校验服务端证书时使用的名称，为空时使用 URL 中的主机名
+	ServerName         string `yaml:"server_name"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+// PushConfig Pushgateway 推送配置，用于无法被抓取的主机
+type PushConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// URL Pushgateway 地址，如 http://pushgateway:9091
+	URL string `yaml:"url"`
+	// Job 推送使用的 job 名称
+	Job string `yaml:"job"`
+	// Grouping 额外的分组标签，未设置 instance 时使用主机名
+	Grouping map[string]string `yaml:"grouping"`
+	// Method put 替换分组内的全部指标，post 只替换同名指标
+	Method string `yaml:"method"`
+	// Interval 推送间隔
+	Interval time.Duration `yaml:"interval"`
+	// Timeout 单次推送的超时时间
+	Timeout time.Duration `yaml:"timeout"`
+	// MaxRetries 推送失败后的重试次数，重试间隔从 RetryBackoff 开始按指数增长，不超过 Interval
+	MaxRetries   int           `yaml:"max_retries"`
+	RetryBackoff time.Duration `yaml:"retry_backoff"`
+	// BasicAuth 基本认证
+	BasicAuth *BasicAuthConfig `yaml:"basic_auth"`
+	// TLS 客户端 TLS 配置
+	TLS *ClientTLSConfig `yaml:"tls"`
+}
+
+// DefaultPushConfig 推送配置的默认值
+var DefaultPushConfig = PushConfig{
+	Job:          "tc_exporter",
+	Method:       PushMethodPut,
+	Interval:     30 * time.Second,
+	Timeout:      10 * time.Second,
+	MaxRetries:   3,
+	RetryBackoff: time.Second,
+}
+
+// validatePush 验证推送配置，未设置的项使用默认值
+func (c *Config) validatePush() error {
+	p, def := &c.Push, DefaultPushConfig
+	if !p.Enabled {
+		return nil
+	}
+	if err := validateHTTPURL(p.URL); err != nil {
+		return err
+	}
+	if p.Interval < 0 || p.Timeout < 0 || p.RetryBackoff < 0 || p.MaxRetries < 0 {
+		return fmt.Errorf("interval, timeout, retry_backoff and max_retries cannot be negative")
+	}
+	if p.Job == "" {
+		p.Job = def.Job
+	}
+	if p.Method == "" {
+		p.Method = def.Method
+	}
+	p.Method = strings.ToLower(p.Method)
+	if p.Method != PushMethodPut && p.Method != PushMethodPost {
+		return fmt.Errorf("method must be put or post, got %q", p.Method)
+	}
+	if p.Interval == 0 {
+		p.Interval = def.Interval
+	}
+	if p.Timeout == 0 {
+		p.Timeout = def.Timeout
+	}
+	if p.MaxRetries == 0 {
+		p.MaxRetries = def.MaxRetries
+	}
+	if p.RetryBackoff == 0 {
+		p.RetryBackoff = def.RetryBackoff
+	}
+	for name := range p.Grouping {
+		if !labelNameRegex.MatchString(name) || name == "job" {
+			return fmt.Errorf("invalid grouping label name %q", name)
+		}
+	}
+	if err := p.BasicAuth.validate(); err != nil {
+		return err
+	}
+	if _, err := p.TLS.Build(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateHTTPURL 验证 http/https 地址
+func validateHTTPURL(raw string) error {
+	if raw == "" {
+		return fmt.Errorf("url is required")
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid url %q: %w", raw, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an absolute http or https URL, got %q", raw)
+	}
+	return nil
+}
+
+func (b *BasicAuthConfig) validate() error {
+	if b == nil {
+		return nil
+	}
+	if b.Username == "" {
+		return fmt.Errorf("basic_auth.username is required")
+	}
+	if b.Password != "" && b.PasswordFile != "" {
+		return fmt.Errorf("basic_auth.password and basic_auth.password_file are mutually exclusive")
+	}
+	_, err := b.Credentials()
+	return err
+}
+
+// Credentials 返回基本认证的密码，配置了 PasswordFile 时读取文件
+func (b *BasicAuthConfig) Credentials() (string, error) {
+	if b.PasswordFile == "" {
+		return string(b.Password), nil
+	}
+	content, err := os.ReadFile(b.PasswordFile)
+	if err != nil {
+		return "", fmt.Errorf("read basic_auth.password_file: %w", err)
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+// Build 根据配置创建 tls.Config，配置为空时返回 nil
+func (t *ClientTLSConfig) Build() (*tls.Config, error) {
+	if t == nil {
+		return nil, nil
+	}
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.InsecureSkipVerify,
+	}
+	if t.CAFile != "" {
+		content, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read tls.ca_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(content) {
+			return nil, fmt.Errorf("no valid certificates in tls.ca_file %s", t.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return nil, fmt.Errorf("tls.cert_file and tls.key_file must be set together")
+	}
+	if t.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load tls client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+// RemoteWriteConfig Prometheus remote_write 发送配置
+// 每次收集的样本先写入磁盘 WAL，再按写入顺序发送，端点不可用期间的样本在恢复后补发
+type RemoteWriteConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// URL remote_write 接收端地址，如 http://prometheus:9090/api/v1/write
+	URL string `yaml:"url"`
+	// Interval 收集并写入 WAL 的间隔
+	Interval time.Duration `yaml:"interval"`
+	// Timeout 单次发送的超时时间
+	Timeout time.Duration `yaml:"timeout"`
+	// ExternalLabels 附加到每个时间序列的标签，未设置 instance 时使用主机名
+	ExternalLabels map[string]string `yaml:"external_labels"`
+	// WALDir WAL 目录，每次收集的样本保存为一个段文件
+	WALDir string `yaml:"wal_dir"`
+	// WALMaxSize WAL 的最大容量，如 "64MB"，超过后丢弃最旧的段
+	WALMaxSize string `yaml:"wal_max_size"`
+	// RetryBackoff 发送失败后的首次重试间隔，之后按指数增长，不超过 Interval
+	RetryBackoff time.Duration `yaml:"retry_backoff"`
+	// BasicAuth 基本认证
+	BasicAuth *BasicAuthConfig `yaml:"basic_auth"`
+	// TLS 客户端 TLS 配置
+	TLS *ClientTLSConfig `yaml:"tls"`
+}
+
+// DefaultRemoteWriteConfig remote_write 配置的默认值
+var DefaultRemoteWriteConfig = RemoteWriteConfig{
+	Interval:     15 * time.Second,
+	Timeout:      30 * time.Second,
+	WALDir:       "/var/lib/uos-tc-exporter/remote-write",
+	WALMaxSize:   "64MB",
+	RetryBackoff: time.Second,
+}
+
+// WALMaxBytes 返回 WALMaxSize 对应的字节数
+func (r RemoteWriteConfig) WALMaxBytes() (int64, error) {
+	size, err := humanize.ParseBytes(r.WALMaxSize)
+	if err != nil {
+		return 0, fmt.Errorf("invalid wal_max_size %q: %w", r.WALMaxSize, err)
+	}
+	return int64(size), nil
+}
+
+// validateRemoteWrite 验证 remote_write 配置，未设置的项使用默认值
+func (c *Config) validateRemoteWrite() error {
+	r, def := &c.RemoteWrite, DefaultRemoteWriteConfig
+	if !r.Enabled {
+		return nil
+	}
+	if err := validateHTTPURL(r.URL); err != nil {
+		return err
+	}
+	if r.Interval < 0 || r.Timeout < 0 || r.RetryBackoff < 0 {
+		return fmt.Errorf("interval, timeout and retry_backoff cannot be negative")
+	}
+	if r.Interval == 0 {
+		r.Interval = def.Interval
+	}
+	if r.Timeout == 0 {
+		r.Timeout = def.Timeout
+	}
+	if r.RetryBackoff == 0 {
+		r.RetryBackoff = def.RetryBackoff
+	}
+	if r.WALDir == "" {
+		r.WALDir = def.WALDir
+	}
+	if !filepath.IsAbs(r.WALDir) {
+		return fmt.Errorf("wal_dir must be an absolute path, got %q", r.WALDir)
+	}
+	if r.WALMaxSize == "" {
+		r.WALMaxSize = def.WALMaxSize
+	}
+	size, err := r.WALMaxBytes()
+	if err != nil {
+		return err
+	}
+	if size < 1<<20 {
+		return fmt.Errorf("wal_max_size must be at least 1MB, got %q", r.WALMaxSize)
+	}
+	for name := range r.ExternalLabels {
+		if !labelNameRegex.MatchString(name) || name == "__name__" {
+			return fmt.Errorf("invalid external label name %q", name)
+		}
+	}
+	if err := r.BasicAuth.validate(); err != nil {
+		return err
+	}
+	if _, err := r.TLS.Build(); err != nil {
+		return err
+	}
+	return nil
+}
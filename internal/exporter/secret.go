@@ -0,0 +1,18 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package exporter
+
+// secretPlaceholder 序列化时代替敏感配置的占位符
+const secretPlaceholder = "<secret>"
+
+// Secret 敏感配置项（密码、认证请求头等），序列化配置（如 SIGUSR1 诊断输出）时被替换为占位符
+type Secret string
+
+// MarshalYAML 非空时输出占位符，避免明文出现在日志或诊断文件中
+func (s Secret) MarshalYAML() (any, error) {
+	if s == "" {
+		return "", nil
+	}
+	return secretPlaceholder, nil
+}
@@ -75,6 +75,8 @@ func (cb *CollectorBase) SetEnabled(enabled bool) {
 }
 
 func (cb *CollectorBase) GetConfig() any {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
 	return cb.config
 }
 func (cb *CollectorBase) SetConfig(config any) error {
@@ -130,3 +132,10 @@ func (cb *CollectorBase) GetLastError() error {
 	defer cb.mu.RUnlock()
 	return cb.lastError
 }
+
+// GetLastCollectTime 获取最后一次采集完成的时间
+func (cb *CollectorBase) GetLastCollectTime() time.Time {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+	return cb.lastCollect
+}
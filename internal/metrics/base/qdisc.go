@@ -8,6 +8,7 @@ import (
 
 	"gitee.com/openeuler/uos-tc-exporter/internal/metrics/interfaces"
 	"gitee.com/openeuler/uos-tc-exporter/internal/tc"
+	"gitee.com/openeuler/uos-tc-exporter/pkg/errors"
 	"github.com/jsimonetti/rtnetlink"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
@@ -46,7 +47,7 @@ func (qb *QdiscBase) CollectMetrics(ch chan<- prometheus.Metric) {
 	nsList, err := tc.GetNetNameSpaceList()
 	if err != nil {
 		qb.Logger.Warnf("Get net namespace list failed: %v", err)
-		qb.SetLastError(err)
+		qb.SetLastError(errors.Wrap(err, errors.ErrCodeNetlinkOperation, "get net namespace list failed"))
 		return
 	}
 
@@ -68,23 +69,36 @@ func (qb *QdiscBase) CollectMetrics(ch chan<- prometheus.Metric) {
 				<-sem
 				wg.Done()
 			}()
-			qb.collectForNamespace(ch, namespace)
+			qb.collectForNamespace(ch, namespace, "")
 		}(ns)
 	}
 	wg.Wait()
 	qb.Logger.Infof("Finished collecting qdisc %s metrics", qb.QdiscType)
 }
 
-// collectForNamespace 收集指定命名空间的指标
-func (qb *QdiscBase) collectForNamespace(ch chan<- prometheus.Metric, ns string) {
+// CollectScope 仅收集指定命名空间的指标，device 非空时只收集该设备，实现 interfaces.ScopedCollector
+func (qb *QdiscBase) CollectScope(ch chan<- prometheus.Metric, ns, device string) {
+	if !qb.Enabled() {
+		return
+	}
+	qb.collectForNamespace(ch, ns, device)
+}
+
+// collectForNamespace 收集指定命名空间的指标，only 非空时只收集该设备
+func (qb *QdiscBase) collectForNamespace(ch chan<- prometheus.Metric, ns, only string) {
 	qb.Logger.Debugf("Start collect for %s", ns)
 	devices, err := tc.GetInterfaceInNetNS(ns)
 	if err != nil {
 		qb.Logger.Warnf("Get interface in netns %s failed: %v", ns, err)
+		qb.SetLastError(errors.Wrap(err, errors.ErrCodeNetlinkOperation, "get interfaces failed").
+			WithContext("namespace", ns))
 		return
 	}
 
 	for _, device := range devices {
+		if only != "" && device.Attributes.Name != only {
+			continue
+		}
 		qb.collectForDevice(ch, ns, device)
 	}
 }
@@ -98,6 +112,8 @@ func (qb *QdiscBase) collectForDevice(ch chan<- prometheus.Metric, ns string, de
 	qdiscs, err := tc.GetQdiscs(deviceIndex, ns)
 	if err != nil {
 		qb.Logger.Warnf("Get qdiscs in netns %s failed: %v", ns, err)
+		qb.SetLastError(errors.Wrap(err, errors.ErrCodeQdiscOperation, "get qdiscs failed").
+			WithContext("namespace", ns).WithContext("device", deviceName))
 		return
 	}
 
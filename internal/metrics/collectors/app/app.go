@@ -1,4 +1,144 @@
 // SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
 // SPDX-License-Identifier: MIT
 
+// Package app 提供导出器自身的应用信息指标
+//
+// 包括构建信息、进程启动时间以及配置重载次数和配置哈希，
+// 便于确认各主机上运行的版本以及配置是否已经生效。
 package app
+
+import (
+	"encoding/hex"
+	"time"
+
+	"gitee.com/openeuler/uos-tc-exporter/internal/metrics/base"
+	"gitee.com/openeuler/uos-tc-exporter/internal/metrics/config"
+	"gitee.com/openeuler/uos-tc-exporter/version"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// 应用指标名称，输出时会加上 tc_exporter_ 前缀
+const (
+	MetricBuildInfo     = "build_info"
+	MetricStartTime     = "start_time_seconds"
+	MetricConfigReloads = "config_reloads_total"
+	MetricConfigHash    = "config_hash"
+)
+
+// buildInfoLabels build_info 指标的标签，与 version.Info 的键一致
+var buildInfoLabels = []string{"version", "revision", "branch", "goversion", "build_time"}
+
+// startTime 进程启动时间，在包初始化时记录
+var startTime = time.Now()
+
+func init() {
+	config.RegisterDefaults("app", DefaultConfig)
+}
+
+// StatsFunc 返回配置管理器统计信息，通常为 ConfigManager.GetStats
+type StatsFunc func() map[string]any
+
+// DefaultConfig 返回应用收集器的默认配置
+func DefaultConfig() *config.CollectorConfig {
+	cfg := config.NewCollectorConfig()
+	cfg.Labels = nil
+
+	buildInfo := config.NewMetricConfig(MetricBuildInfo, "A metric with a constant '1' value labeled by version, revision, branch, goversion and build_time", "gauge")
+	buildInfo.SetLabels(buildInfoLabels)
+	started := config.NewMetricConfig(MetricStartTime, "Start time of the exporter process since unix epoch in seconds", "gauge")
+	started.SetLabels(nil)
+	reloads := config.NewMetricConfig(MetricConfigReloads, "Number of successful configuration reloads", "counter")
+	reloads.SetLabels(nil)
+	hash := config.NewMetricConfig(MetricConfigHash, "Hash of the currently loaded configuration file, 0 if running with defaults", "gauge")
+	hash.SetLabels(nil)
+
+	cfg.AddMetric(MetricBuildInfo, *buildInfo)
+	cfg.AddMetric(MetricStartTime, *started)
+	cfg.AddMetric(MetricConfigReloads, *reloads)
+	cfg.AddMetric(MetricConfigHash, *hash)
+	return cfg
+}
+
+// AppCollector 应用信息收集器
+type AppCollector struct {
+	*base.CollectorBase
+	stats StatsFunc
+}
+
+// NewAppCollector 创建应用信息收集器，stats 为空时不输出配置相关指标
+func NewAppCollector(cfg config.CollectorConfig, stats StatsFunc, logger *logrus.Logger) *AppCollector {
+	if logger == nil {
+		logger = logrus.StandardLogger()
+	}
+	ac := &AppCollector{
+		CollectorBase: base.NewCollectorBase("app", "app", "Exporter build and runtime information", &cfg, logger),
+		stats:         stats,
+	}
+	ac.initializeMetrics(&cfg)
+	ac.SetCollectFunc(ac.collect)
+	return ac
+}
+
+func (ac *AppCollector) initializeMetrics(cfg *config.CollectorConfig) {
+	for metricName, metricConfig := range cfg.GetMetrics() {
+		if !metricConfig.IsEnabled() {
+			continue
+		}
+		desc := prometheus.NewDesc(
+			"tc_exporter_"+metricName,
+			metricConfig.GetHelp(),
+			metricConfig.GetLabels(), metricConfig.GetConstLabels(),
+		)
+		ac.AddMetric(metricName, desc)
+	}
+}
+
+// collect 输出构建信息、启动时间及配置状态
+func (ac *AppCollector) collect(ch chan<- prometheus.Metric) {
+	info := version.Info()
+	labels := make([]string, 0, len(buildInfoLabels))
+	for _, name := range buildInfoLabels {
+		labels = append(labels, info[name])
+	}
+	ac.send(ch, MetricBuildInfo, prometheus.GaugeValue, 1, labels...)
+	ac.send(ch, MetricStartTime, prometheus.GaugeValue, float64(startTime.UnixNano())/1e9)
+
+	if ac.stats == nil {
+		return
+	}
+	stats := ac.stats()
+	if count, ok := stats["reload_count"].(int); ok {
+		ac.send(ch, MetricConfigReloads, prometheus.CounterValue, float64(count))
+	}
+	if hash, ok := stats["config_hash"].(string); ok {
+		ac.send(ch, MetricConfigHash, prometheus.GaugeValue, hashValue(hash))
+	}
+}
+
+// send 按配置输出指标，未启用的指标会被忽略
+func (ac *AppCollector) send(ch chan<- prometheus.Metric, metricName string, valueType prometheus.ValueType, value float64, labels ...string) {
+	desc, ok := ac.GetMetric(metricName)
+	if !ok {
+		return
+	}
+	metric, err := prometheus.NewConstMetric(desc, valueType, value, labels...)
+	if err != nil {
+		ac.Logger.Warnf("Failed to build app metric %s: %v", metricName, err)
+		return
+	}
+	ch <- metric
+}
+
+// hashValue 取十六进制哈希的前 48 位作为指标值，保证 float64 可精确表示
+func hashValue(hash string) float64 {
+	raw, err := hex.DecodeString(hash)
+	if err != nil || len(raw) < 6 {
+		return 0
+	}
+	var value uint64
+	for _, b := range raw[:6] {
+		value = value<<8 | uint64(b)
+	}
+	return float64(value)
+}
@@ -1,4 +1,231 @@
 // SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
 // SPDX-License-Identifier: MIT
 
+// Package business 提供基于 TC 统计计算的业务派生指标
+//
+// 派生指标由同一次快照中的 qdisc/class 计数器计算得到，包括丢包率、
+// 相对于 HTB/TBF 配置速率或链路速率的利用率、估算排队时延以及
+// HTB class 的借用比例，方便直接基于这些信号告警。
 package business
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gitee.com/openeuler/uos-tc-exporter/internal/metrics/base"
+	"gitee.com/openeuler/uos-tc-exporter/internal/metrics/config"
+	"gitee.com/openeuler/uos-tc-exporter/internal/tc"
+	"gitee.com/openeuler/uos-tc-exporter/pkg/errors"
+	gotc "github.com/florianl/go-tc"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// 业务派生指标名称，输出时会加上 tc_ 前缀
+const (
+	MetricDropRatio   = "drop_ratio"
+	MetricUtilization = "utilization_ratio"
+	MetricQueueDelay  = "queue_delay_seconds"
+	MetricBorrowRatio = "htb_borrow_ratio"
+)
+
+const sysClassNetDir = "/sys/class/net"
+
+func init() {
+	config.RegisterDefaults("business", DefaultConfig)
+}
+
+// DefaultConfig 返回业务收集器的默认配置
+func DefaultConfig() *config.CollectorConfig {
+	objectLabels := []string{"namespace", "device", "object", "kind", "handle", "parent"}
+	cfg := config.NewCollectorConfig()
+	cfg.Labels = objectLabels
+
+	dropRatio := config.NewMetricConfig(MetricDropRatio, "Ratio of dropped packets to offered packets over the last rate window", "gauge")
+	dropRatio.SetLabels(objectLabels)
+	utilization := config.NewMetricConfig(MetricUtilization, "Throughput divided by the configured HTB/TBF rate or the link speed, may exceed 1 when borrowing", "gauge")
+	utilization.SetLabels(append(append([]string{}, objectLabels...), "basis"))
+	queueDelay := config.NewMetricConfig(MetricQueueDelay, "Estimated queueing delay computed as backlog divided by dequeue rate", "gauge")
+	queueDelay.SetLabels(objectLabels)
+	borrowRatio := config.NewMetricConfig(MetricBorrowRatio, "Ratio of packets sent with tokens borrowed from the parent HTB class", "gauge")
+	borrowRatio.SetLabels(objectLabels)
+
+	cfg.AddMetric(MetricDropRatio, *dropRatio)
+	cfg.AddMetric(MetricUtilization, *utilization)
+	cfg.AddMetric(MetricQueueDelay, *queueDelay)
+	cfg.AddMetric(MetricBorrowRatio, *borrowRatio)
+	return cfg
+}
+
+// BusinessCollector 业务派生指标收集器
+type BusinessCollector struct {
+	*base.CollectorBase
+	stateMu sync.Mutex
+	// rates 每个对象上一次的快照，由 stateMu 保护
+	rates *RateTracker
+}
+
+// NewBusinessCollector 创建业务派生指标收集器，速率和比例按不短于 minRateWindow 的窗口计算
+func NewBusinessCollector(cfg config.CollectorConfig, minRateWindow time.Duration, logger *logrus.Logger) *BusinessCollector {
+	if logger == nil {
+		logger = logrus.StandardLogger()
+	}
+	bc := &BusinessCollector{
+		CollectorBase: base.NewCollectorBase("business", "business", "Derived TC business metrics", &cfg, logger),
+		rates:         NewRateTracker(minRateWindow),
+	}
+	bc.initializeMetrics(&cfg)
+	bc.SetCollectFunc(bc.collect)
+	return bc
+}
+
+func (bc *BusinessCollector) initializeMetrics(cfg *config.CollectorConfig) {
+	for metricName, metricConfig := range cfg.GetMetrics() {
+		if !metricConfig.IsEnabled() {
+			continue
+		}
+		desc := prometheus.NewDesc(
+			"tc_"+metricName,
+			metricConfig.GetHelp(),
+			metricConfig.GetLabels(), metricConfig.GetConstLabels(),
+		)
+		bc.AddMetric(metricName, desc)
+	}
+}
+
+// collect 遍历所有命名空间和设备，基于同一次快照计算派生指标
+func (bc *BusinessCollector) collect(ch chan<- prometheus.Metric) {
+	nsList, err := tc.GetNetNameSpaceList()
+	if err != nil {
+		bc.Logger.Warnf("Get net namespace list failed: %v", err)
+		bc.SetLastError(errors.Wrap(err, errors.ErrCodeNetlinkOperation, "get net namespace list failed"))
+		return
+	}
+
+	// 串行化采集，保证相邻快照的差值计算一致
+	bc.stateMu.Lock()
+	defer bc.stateMu.Unlock()
+
+	for _, ns := range nsList {
+		bc.collectForNamespace(ch, ns, "")
+	}
+
+	// 清理已消失对象的历史快照
+	bc.rates.Prune()
+}
+
+// CollectScope 仅收集指定命名空间的派生指标，device 非空时只收集该设备，实现 interfaces.ScopedCollector
+// 只更新范围内对象的快照，不清理范围外的对象
+func (bc *BusinessCollector) CollectScope(ch chan<- prometheus.Metric, ns, device string) {
+	if !bc.Enabled() {
+		return
+	}
+	bc.stateMu.Lock()
+	defer bc.stateMu.Unlock()
+	bc.collectForNamespace(ch, ns, device)
+}
+
+// collectForNamespace 计算指定命名空间中设备的派生指标，only 非空时只处理该设备
+func (bc *BusinessCollector) collectForNamespace(ch chan<- prometheus.Metric, ns, only string) {
+	devices, err := tc.GetInterfaceInNetNS(ns)
+	if err != nil {
+		bc.Logger.Warnf("Get interface in netns %s failed: %v", ns, err)
+		bc.SetLastError(errors.Wrap(err, errors.ErrCodeNetlinkOperation, "get interfaces failed").
+			WithContext("namespace", ns))
+		return
+	}
+	for _, device := range devices {
+		if device.Attributes == nil || (only != "" && device.Attributes.Name != only) {
+			continue
+		}
+		bc.collectForDevice(ch, ns, device.Index, device.Attributes.Name)
+	}
+}
+
+// collectForDevice 计算单个设备上所有 qdisc/class 的派生指标
+func (bc *BusinessCollector) collectForDevice(ch chan<- prometheus.Metric, ns string, index uint32, deviceName string) {
+	now := time.Now()
+	linkRate := linkSpeed(ns, deviceName)
+
+	qdiscs, err := tc.GetQdiscs(index, ns)
+	if err != nil {
+		bc.Logger.Warnf("Get qdiscs of %s in netns %s failed: %v", deviceName, ns, err)
+		bc.SetLastError(errors.Wrap(err, errors.ErrCodeQdiscOperation, "get qdiscs failed").
+			WithContext("namespace", ns).WithContext("device", deviceName))
+	}
+	for i := range qdiscs {
+		bc.emit(ch, ns, deviceName, "qdisc", &qdiscs[i], linkRate, now)
+	}
+
+	classes, err := tc.GetClasses(index, ns)
+	if err != nil {
+		bc.Logger.Debugf("Get classes of %s in netns %s failed: %v", deviceName, ns, err)
+		return
+	}
+	for i := range classes {
+		bc.emit(ch, ns, deviceName, "class", &classes[i], linkRate, now)
+	}
+}
+
+// emit 计算并输出单个 TC 对象的派生指标
+func (bc *BusinessCollector) emit(ch chan<- prometheus.Metric, ns, deviceName, object string, obj *gotc.Object, linkRate float64, now time.Time) {
+	handle := tc.FormatHandle(obj.Handle)
+	cur, d, ok := bc.rates.update(ObjectKey(ns, deviceName, object, handle), obj, now)
+	if !ok {
+		return
+	}
+
+	labels := []string{ns, deviceName, object, obj.Kind, handle, tc.FormatHandle(obj.Parent)}
+	if d.hasDropRatio {
+		bc.send(ch, MetricDropRatio, d.dropRatio, labels...)
+	}
+	if d.hasThroughput {
+		rate, basis := configuredRate(obj)
+		if rate == 0 && linkRate > 0 {
+			rate, basis = linkRate, "link_speed"
+		}
+		if rate > 0 {
+			bc.send(ch, MetricUtilization, d.throughput/rate, append(labels, basis)...)
+		}
+	}
+	if delay, ok := queueDelay(cur.backlog, d.throughput); ok {
+		bc.send(ch, MetricQueueDelay, delay, labels...)
+	}
+	if d.hasBorrow {
+		bc.send(ch, MetricBorrowRatio, d.borrowRatio, labels...)
+	}
+}
+
+// send 按配置输出指标，未启用的指标会被忽略
+func (bc *BusinessCollector) send(ch chan<- prometheus.Metric, metricName string, value float64, labels ...string) {
+	desc, ok := bc.GetMetric(metricName)
+	if !ok {
+		return
+	}
+	metric, err := prometheus.NewConstMetric(desc, prometheus.GaugeValue, value, labels...)
+	if err != nil {
+		bc.Logger.Warnf("Failed to build business metric %s: %v", metricName, err)
+		return
+	}
+	ch <- metric
+}
+
+// linkSpeed 读取链路速率（字节/秒），仅默认命名空间可通过 sysfs 获取
+func linkSpeed(ns, deviceName string) float64 {
+	if ns != tc.DefaultNetNS {
+		return 0
+	}
+	content, err := os.ReadFile(filepath.Join(sysClassNetDir, deviceName, "speed"))
+	if err != nil {
+		return 0
+	}
+	mbps, err := strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+	if err != nil || mbps <= 0 {
+		return 0
+	}
+	return float64(mbps) * 1e6 / 8
+}
@@ -0,0 +1,136 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package business
+
+import (
+	"time"
+
+	gotc "github.com/florianl/go-tc"
+)
+
+// snapshot 一个 qdisc/class 在某一时刻的计数器快照
+type snapshot struct {
+	bytes   uint64
+	packets uint64
+	drops   uint64
+	backlog uint64
+	bps     uint64
+	borrows uint64
+	lends   uint64
+	htb     bool
+	at      time.Time
+}
+
+// derived 由相邻两次快照计算出的派生信号
+type derived struct {
+	dropRatio     float64
+	hasDropRatio  bool
+	throughput    float64
+	hasThroughput bool
+	packetRate    float64
+	dropRate      float64
+	borrowRatio   float64
+	hasBorrow     bool
+}
+
+// takeSnapshot 从 TC 对象中读取计数器，优先使用 Stats2
+func takeSnapshot(obj *gotc.Object, at time.Time) (snapshot, bool) {
+	s := snapshot{at: at}
+	switch {
+	case obj.Stats2 != nil:
+		s.bytes = obj.Stats2.Bytes
+		s.packets = uint64(obj.Stats2.Packets)
+		s.drops = uint64(obj.Stats2.Drops)
+		s.backlog = uint64(obj.Stats2.Backlog)
+		if obj.Stats != nil {
+			s.bps = uint64(obj.Stats.Bps)
+		}
+	case obj.Stats != nil:
+		s.bytes = obj.Stats.Bytes
+		s.packets = uint64(obj.Stats.Packets)
+		s.drops = uint64(obj.Stats.Drops)
+		s.backlog = uint64(obj.Stats.Backlog)
+		s.bps = uint64(obj.Stats.Bps)
+	default:
+		return s, false
+	}
+	if obj.XStats != nil && obj.XStats.Htb != nil {
+		s.htb = true
+		s.borrows = uint64(obj.XStats.Htb.Borrows)
+		s.lends = uint64(obj.XStats.Htb.Lends)
+	}
+	return s, true
+}
+
+// counterReset 判断 cur 相对 prev 是否发生了计数器回绕或对象重建
+func counterReset(prev, cur snapshot) bool {
+	return cur.packets < prev.packets || cur.drops < prev.drops ||
+		cur.bytes < prev.bytes || cur.borrows < prev.borrows || cur.lends < prev.lends
+}
+
+// derive 由 prev 到 cur 的差值计算派生信号
+// prev 为空或计数器回绕时没有可用的窗口，不输出比例和差值速率，吞吐量仅在内核速率估计器有值时可用
+func derive(prev *snapshot, cur snapshot) derived {
+	var d derived
+	elapsed := 0.0
+	if prev != nil && !counterReset(*prev, cur) {
+		elapsed = cur.at.Sub(prev.at).Seconds()
+	}
+	if elapsed <= 0 {
+		if cur.bps > 0 {
+			// 使用内核速率估计器（需配置 estimator 才有值）
+			d.throughput = float64(cur.bps)
+			d.hasThroughput = true
+		}
+		return d
+	}
+
+	packets := cur.packets - prev.packets
+	drops := cur.drops - prev.drops
+	if total := packets + drops; total > 0 {
+		d.dropRatio = float64(drops) / float64(total)
+		d.hasDropRatio = true
+	}
+
+	d.throughput = float64(cur.bytes-prev.bytes) / elapsed
+	d.hasThroughput = true
+	d.packetRate = float64(packets) / elapsed
+	d.dropRate = float64(drops) / elapsed
+
+	if cur.htb {
+		borrows, lends := cur.borrows-prev.borrows, cur.lends-prev.lends
+		if total := borrows + lends; total > 0 {
+			d.borrowRatio = float64(borrows) / float64(total)
+			d.hasBorrow = true
+		}
+	}
+	return d
+}
+
+// queueDelay 估算排队时延：积压字节数除以出队速率
+func queueDelay(backlog uint64, throughput float64) (float64, bool) {
+	if backlog == 0 {
+		return 0, true
+	}
+	if throughput <= 0 {
+		return 0, false
+	}
+	return float64(backlog) / throughput, true
+}
+
+// configuredRate 返回 HTB class 或 TBF qdisc 配置的速率（字节/秒）
+func configuredRate(obj *gotc.Object) (float64, string) {
+	if obj.Htb != nil && obj.Htb.Parms != nil {
+		if obj.Htb.Rate64 != nil && *obj.Htb.Rate64 > 0 {
+			return float64(*obj.Htb.Rate64), "htb_rate"
+		}
+		if obj.Htb.Parms.Rate.Rate > 0 {
+			return float64(obj.Htb.Parms.Rate.Rate), "htb_rate"
+		}
+	}
+	if obj.Tbf != nil && obj.Tbf.Parms != nil && obj.Tbf.Parms.Rate.Rate > 0 {
+		return float64(obj.Tbf.Parms.Rate.Rate), "tbf_rate"
+	}
+	return 0, ""
+}
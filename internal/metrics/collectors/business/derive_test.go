@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package business
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	gotc "github.com/florianl/go-tc"
+)
+
+func TestDerive(t *testing.T) {
+	start := time.Unix(1000, 0)
+	prev := snapshot{bytes: 1000, packets: 90, drops: 10, htb: true, borrows: 10, lends: 30, at: start}
+
+	tests := []struct {
+		name           string
+		prev           *snapshot
+		cur            snapshot
+		hasRatios      bool
+		wantDropRatio  float64
+		wantThroughput float64
+		wantBorrow     float64
+		hasThroughput  bool
+	}{
+		{
+			name: "first snapshot has no window",
+			cur:  snapshot{bytes: 1000, packets: 90, drops: 10, htb: true, borrows: 10, lends: 30, at: start},
+		},
+		{
+			name:           "first snapshot uses the kernel rate estimator",
+			cur:            snapshot{bytes: 1000, packets: 90, drops: 10, bps: 500, at: start},
+			wantThroughput: 500,
+			hasThroughput:  true,
+		},
+		{
+			name:           "delta between snapshots",
+			prev:           &prev,
+			cur:            snapshot{bytes: 3000, packets: 170, drops: 30, htb: true, borrows: 30, lends: 50, at: start.Add(2 * time.Second)},
+			hasRatios:      true,
+			wantDropRatio:  0.2,
+			wantThroughput: 1000,
+			wantBorrow:     0.5,
+			hasThroughput:  true,
+		},
+		{
+			name: "counter reset has no window",
+			prev: &prev,
+			cur:  snapshot{bytes: 100, packets: 3, drops: 1, htb: true, borrows: 1, lends: 3, at: start.Add(time.Second)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := derive(tt.prev, tt.cur)
+			if d.hasDropRatio != tt.hasRatios || math.Abs(d.dropRatio-tt.wantDropRatio) > 1e-9 {
+				t.Errorf("dropRatio = %v (%v), want %v (%v)", d.dropRatio, d.hasDropRatio, tt.wantDropRatio, tt.hasRatios)
+			}
+			if d.hasThroughput != tt.hasThroughput || math.Abs(d.throughput-tt.wantThroughput) > 1e-9 {
+				t.Errorf("throughput = %v (%v), want %v", d.throughput, d.hasThroughput, tt.wantThroughput)
+			}
+			if d.hasBorrow != tt.hasRatios || math.Abs(d.borrowRatio-tt.wantBorrow) > 1e-9 {
+				t.Errorf("borrowRatio = %v (%v), want %v (%v)", d.borrowRatio, d.hasBorrow, tt.wantBorrow, tt.hasRatios)
+			}
+		})
+	}
+}
+
+func TestQueueDelay(t *testing.T) {
+	if delay, ok := queueDelay(0, 0); !ok || delay != 0 {
+		t.Errorf("empty queue should report zero delay, got %v %v", delay, ok)
+	}
+	if _, ok := queueDelay(1500, 0); ok {
+		t.Errorf("backlog without dequeue rate should not report a delay")
+	}
+	if delay, ok := queueDelay(1_000_000, 500_000); !ok || delay != 2 {
+		t.Errorf("queueDelay(1MB, 500KB/s) = %v %v, want 2s", delay, ok)
+	}
+}
+
+func TestRateTracker(t *testing.T) {
+	start := time.Unix(1000, 0)
+	obj := func(bytes uint64, packets, drops, backlog uint32) *gotc.Object {
+		return &gotc.Object{Attribute: gotc.Attribute{
+			Stats2: &gotc.Stats2{Bytes: bytes, Packets: packets, Drops: drops, Backlog: backlog},
+		}}
+	}
+	tracker := NewRateTracker(0)
+	key := ObjectKey("default", "eth0", "qdisc", "1:0")
+
+	if r, ok := tracker.Rates(key, obj(1000, 10, 1, 0), start); !ok || r.HasThroughput || r.PacketRate != 0 {
+		t.Errorf("first sample = %+v %v, want no rates", r, ok)
+	}
+	r, ok := tracker.Rates(key, obj(3000, 30, 5, 4000), start.Add(2*time.Second))
+	if !ok || r.Throughput != 1000 || r.PacketRate != 10 || r.DropRate != 2 || r.Drops != 5 {
+		t.Errorf("second sample = %+v %v", r, ok)
+	}
+	if !r.HasQueueDelay || r.QueueDelay != 4 {
+		t.Errorf("queue delay = %v (%v), want 4s", r.QueueDelay, r.HasQueueDelay)
+	}
+	if _, ok := tracker.Rates("other", &gotc.Object{}, start); ok {
+		t.Error("object without stats should be skipped")
+	}
+
+	// 一轮没有更新过的对象在 Prune 后被清理，再次出现时重新从首次采样开始
+	tracker.Prune()
+	tracker.Prune()
+	if r, _ := tracker.Rates(key, obj(5000, 50, 5, 0), start.Add(4*time.Second)); r.HasThroughput {
+		t.Errorf("sample after prune = %+v, want no rates", r)
+	}
+}
+
+func TestRateTrackerMinWindow(t *testing.T) {
+	start := time.Unix(1000, 0)
+	obj := func(bytes uint64, packets, drops uint32) *gotc.Object {
+		return &gotc.Object{Attribute: gotc.Attribute{
+			Stats2: &gotc.Stats2{Bytes: bytes, Packets: packets, Drops: drops},
+		}}
+	}
+	tracker := NewRateTracker(5 * time.Second)
+	key := ObjectKey("default", "eth0", "class", "1:10")
+
+	tracker.Rates(key, obj(0, 0, 0), start)
+	// 窗口不足 5 秒的调用（如另一个 Prometheus 副本紧接着抓取）不计算速率，也不移动窗口起点
+	if r, _ := tracker.Rates(key, obj(100, 1, 0), start.Add(10*time.Millisecond)); r.HasThroughput {
+		t.Errorf("rates inside the first window = %+v, want none", r)
+	}
+	r, _ := tracker.Rates(key, obj(6000, 60, 6), start.Add(6*time.Second))
+	if r.Throughput != 1000 || r.PacketRate != 10 || r.DropRate != 1 {
+		t.Errorf("rates over 6s window = %+v, want 1000 B/s, 10 pkt/s, 1 drop/s", r)
+	}
+	// 下一个窗口内的调用复用上一个窗口的结果
+	if cached, _ := tracker.Rates(key, obj(6100, 61, 6), start.Add(6*time.Second+time.Millisecond)); cached.Throughput != 1000 {
+		t.Errorf("rates inside the next window = %+v, want the previous window", cached)
+	}
+	// 计数器回绕时立即开始新窗口
+	if reset, _ := tracker.Rates(key, obj(10, 1, 0), start.Add(7*time.Second)); reset.HasThroughput {
+		t.Errorf("rates after counter reset = %+v, want none", reset)
+	}
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package business
+
+import (
+	"strings"
+	"time"
+
+	gotc "github.com/florianl/go-tc"
+)
+
+// Rates 由相邻两次快照计算出的 TC 对象速率和队列状态
+type Rates struct {
+	// Throughput 出队速率（字节/秒），首次采样时使用内核速率估计器，HasThroughput 为 false 时未知
+	Throughput    float64
+	HasThroughput bool
+	// PacketRate、DropRate 每秒发送和丢弃的包数，首次采样或计数器回绕后的第一个窗口内为 0
+	PacketRate float64
+	DropRate   float64
+	// Drops 累计丢包数，Backlog 当前积压字节数
+	Drops   uint64
+	Backlog uint64
+	// QueueDelay 估算排队时延（秒），HasQueueDelay 为 false 时无法估算
+	QueueDelay    float64
+	HasQueueDelay bool
+}
+
+// DefaultMinRateWindow 业务指标计算速率和比例的最小时间窗口
+// 多个 Prometheus 副本、collect[] 抓取、推送和预热都会触发收集，相邻两次调用可能只相隔几毫秒
+const DefaultMinRateWindow = 5 * time.Second
+
+// RateTracker 保存每个 TC 对象的窗口起始快照，业务指标收集器和 top 子命令共用
+// 速率和比例只在窗口长度达到 minWindow 时重新计算，窗口内的调用复用上一个窗口的结果，
+// 不会因为调用方之间的间隔过短得到不稳定的值。不是并发安全的，调用方需要串行化
+type RateTracker struct {
+	minWindow time.Duration
+	// previous 当前窗口的起始快照，键为 ObjectKey
+	previous map[string]snapshot
+	// last 最近一个完整窗口计算出的派生信号
+	last map[string]derived
+	// seen 上次 Prune 之后更新过的对象
+	seen map[string]bool
+}
+
+// NewRateTracker 创建空的 RateTracker，minWindow 为 0 时每次调用都重新计算
+func NewRateTracker(minWindow time.Duration) *RateTracker {
+	return &RateTracker{
+		minWindow: minWindow,
+		previous:  make(map[string]snapshot),
+		last:      make(map[string]derived),
+		seen:      make(map[string]bool),
+	}
+}
+
+// ObjectKey 返回对象在 RateTracker 中的键，object 为 qdisc 或 class
+func ObjectKey(ns, device, object, handle string) string {
+	return strings.Join([]string{ns, device, object, handle}, "/")
+}
+
+// Rates 记录 obj 在 at 时刻的快照并返回最近一个窗口的速率，对象没有统计信息时返回 false
+func (t *RateTracker) Rates(key string, obj *gotc.Object, at time.Time) (Rates, bool) {
+	cur, d, ok := t.update(key, obj, at)
+	if !ok {
+		return Rates{}, false
+	}
+	r := Rates{
+		Throughput:    d.throughput,
+		HasThroughput: d.hasThroughput,
+		PacketRate:    d.packetRate,
+		DropRate:      d.dropRate,
+		Drops:         cur.drops,
+		Backlog:       cur.backlog,
+	}
+	r.QueueDelay, r.HasQueueDelay = queueDelay(cur.backlog, d.throughput)
+	return r, true
+}
+
+// update 记录快照并返回派生信号，窗口不足 minWindow 时返回上一个窗口的结果且不移动窗口起点
+func (t *RateTracker) update(key string, obj *gotc.Object, at time.Time) (snapshot, derived, bool) {
+	cur, ok := takeSnapshot(obj, at)
+	if !ok {
+		return cur, derived{}, false
+	}
+	t.seen[key] = true
+	prev, exists := t.previous[key]
+	if exists && cur.at.Sub(prev.at) < t.minWindow && !counterReset(prev, cur) {
+		return cur, t.last[key], true
+	}
+	var d derived
+	if exists {
+		d = derive(&prev, cur)
+	} else {
+		d = derive(nil, cur)
+	}
+	t.previous[key] = cur
+	t.last[key] = d
+	return cur, d, true
+}
+
+// Prune 清理上次 Prune 之后没有更新过的对象（已删除的 qdisc/class）的快照
+func (t *RateTracker) Prune() {
+	for key := range t.previous {
+		if !t.seen[key] {
+			delete(t.previous, key)
+			delete(t.last, key)
+		}
+	}
+	t.seen = make(map[string]bool)
+}
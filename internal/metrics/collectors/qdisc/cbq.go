@@ -11,23 +11,6 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// func init() {
-// 	mc := map[string]config.MetricConfig{
-// 		"cbq_avg_idle":    NewCbqConfig("cbq_avg_idle", "CBQ avg idle xstat"),
-// 		"cbq_borrows":     NewCbqConfig("cbq_borrows", "CBQ borrows xstat"),
-// 		"cbq_overactions": NewCbqConfig("cbq_overactions", "CBQ overactions xstat"),
-// 		"cbq_undertime":   NewCbqConfig("cbq_undertime", "CBQ undetime xstat"),
-// 	}
-// 	code := NewCbqCollector(config.CollectorConfig{
-// 		Enabled:    true,
-// 		Timeout:    5,
-// 		RetryCount: 3,
-// 		Metrics:    mc},
-// 		logrus.StandardLogger(),
-// 	)
-// 	exporter.Register(code)
-// }
-
 type CbqCollector struct {
 	*base.QdiscBase
 }
@@ -57,10 +40,13 @@ func NewCbqCollector(cfg config.CollectorConfig, logger *logrus.Logger) *CbqColl
 func (c *CbqCollector) initializeMetrics(cfg *config.CollectorConfig) {
 	labelNames := c.LabelNames
 	for metricName, metricConfig := range cfg.GetMetrics() {
+		if !metricConfig.IsEnabled() {
+			continue
+		}
 		desc := prometheus.NewDesc(
 			"qdisc_cbq_"+metricName,
 			metricConfig.GetHelp(),
-			labelNames, nil,
+			labelNames, metricConfig.GetConstLabels(),
 		)
 		c.AddMetric(metricName, desc)
 		c.AddSupportedMetric(metricName)
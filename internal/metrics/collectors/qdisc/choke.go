@@ -11,24 +11,6 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// func init() {
-// 	mc := map[string]config.MetricConfig{
-// 		"choke_early":   *config.NewMetricConfig("choke_early", "Choke early xstat", "choke"),
-// 		"choke_marked":  *config.NewMetricConfig("choke_marked", "Choke marked xstat", "choke"),
-// 		"choke_matched": *config.NewMetricConfig("choke_matched", "Choke matched xstat", "choke"),
-// 		"choke_other":   *config.NewMetricConfig("choke_other", "Choke other xstat", "choke"),
-// 		"choke_pdrop":   *config.NewMetricConfig("choke_pdrop", "Choke pdrop xstat", "choke"),
-// 	}
-// 	code := NewChokeCollector(config.CollectorConfig{
-// 		Enabled:    true,
-// 		Timeout:    5,
-// 		RetryCount: 3,
-// 		Metrics:    mc},
-// 		logrus.StandardLogger(),
-// 	)
-// 	exporter.Register(code)
-// }
-
 type ChokeCollector struct {
 	*base.QdiscBase
 }
@@ -58,10 +40,13 @@ func NewChokeCollector(cfg config.CollectorConfig, logger *logrus.Logger) *Choke
 func (c *ChokeCollector) initializeMetrics(cfg *config.CollectorConfig) {
 	labelNames := c.LabelNames
 	for metricName, metricConfig := range cfg.GetMetrics() {
+		if !metricConfig.IsEnabled() {
+			continue
+		}
 		desc := prometheus.NewDesc(
 			"qdisc_choke_"+metricName,
 			metricConfig.GetHelp(),
-			labelNames, nil,
+			labelNames, metricConfig.GetConstLabels(),
 		)
 		c.AddMetric(metricName, desc)
 		c.AddSupportedMetric(metricName)
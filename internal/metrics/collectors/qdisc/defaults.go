@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package qdisc
+
+import (
+	"gitee.com/openeuler/uos-tc-exporter/internal/metrics/config"
+)
+
+func init() {
+	config.RegisterDefaults("qdisc_qdisc", DefaultQdiscConfig)
+	config.RegisterDefaults("qdisc_codel", DefaultCodelConfig)
+	config.RegisterDefaults("qdisc_cbq", DefaultCbqConfig)
+	config.RegisterDefaults("qdisc_choke", DefaultChokeConfig)
+}
+
+// newDefaultConfig 使用给定指标创建收集器默认配置
+func newDefaultConfig(mc map[string]config.MetricConfig) *config.CollectorConfig {
+	cfg := config.NewCollectorConfig()
+	cfg.Metrics = mc
+	return cfg
+}
+
+// DefaultQdiscConfig 返回通用 qdisc 收集器的默认配置
+func DefaultQdiscConfig() *config.CollectorConfig {
+	return newDefaultConfig(map[string]config.MetricConfig{
+		"bytes_total":      *config.NewMetricConfig("bytes_total", "QdiscPie byte counter", "qdisc"),
+		"packets_total":    *config.NewMetricConfig("packets_total", "QdiscPie packet counter", "qdisc"),
+		"drops_total":      *config.NewMetricConfig("drops_total", "QdiscPie queue drops", "qdisc"),
+		"overlimits_total": *config.NewMetricConfig("overlimits", "QdiscPie queue overlimits", "qdisc"),
+		"bps":              *config.NewMetricConfig("bps", "QdiscPie bytes per second", "qdisc"),
+		"pps":              *config.NewMetricConfig("pps", "QdiscPie packets per second", "qdisc"),
+		"qlen":             *config.NewMetricConfig("qlen", "QdiscPie current queue length", "qdisc"),
+		"backlog":          *config.NewMetricConfig("backlog", "QdiscPie current backlog in bytes", "qdisc"),
+		"requeues_total":   *config.NewMetricConfig("requeues_total", "QdiscPie number of requeues", "qdisc"),
+	})
+}
+
+// DefaultCodelConfig 返回 codel 收集器的默认配置
+func DefaultCodelConfig() *config.CollectorConfig {
+	return newDefaultConfig(map[string]config.MetricConfig{
+		"ce_mark":        *config.NewMetricConfig("ce_mark", "Number of packets marked with CE (Congestion Experienced) by CoDel", "codel"),
+		"count":          *config.NewMetricConfig("count", "Current number of packets in the CoDel queue", "codel"),
+		"drop_next":      *config.NewMetricConfig("drop_next", "Time when the next packet will be dropped by CoDel", "codel"),
+		"drop_overlimit": *config.NewMetricConfig("drop_overlimit", "Number of packets dropped because they exceeded the CoDel limit", "codel"),
+		"dropping":       *config.NewMetricConfig("dropping", "Indicates whether CoDel is currently dropping packets", "codel"),
+		"ecn_mark":       *config.NewMetricConfig("ecn_mark", "Number of packets marked with ECN (Explicit Congestion Notification) by CoDel", "codel"),
+		"ldelay":         *config.NewMetricConfig("ldelay", "Last measured delay of packets in the CoDel queue (in microseconds)", "codel"),
+		"max_packet":     *config.NewMetricConfig("max_packet", "Maximum packet size handled by CoDel (in bytes)", "codel"),
+	})
+}
+
+// newOptionalConfig 创建默认不启用的收集器配置，需在 collectors 配置段中显式启用
+func newOptionalConfig(mc map[string]config.MetricConfig) *config.CollectorConfig {
+	cfg := newDefaultConfig(mc)
+	cfg.SetEnabled(false)
+	return cfg
+}
+
+// DefaultCbqConfig 返回 cbq 收集器的默认配置，默认不启用
+func DefaultCbqConfig() *config.CollectorConfig {
+	return newOptionalConfig(map[string]config.MetricConfig{
+		"cbq_avg_idle":    NewCbqConfig("cbq_avg_idle", "CBQ avg idle xstat"),
+		"cbq_borrows":     NewCbqConfig("cbq_borrows", "CBQ borrows xstat"),
+		"cbq_overactions": NewCbqConfig("cbq_overactions", "CBQ overactions xstat"),
+		"cbq_undertime":   NewCbqConfig("cbq_undertime", "CBQ undetime xstat"),
+	})
+}
+
+// DefaultChokeConfig 返回 choke 收集器的默认配置，默认不启用
+func DefaultChokeConfig() *config.CollectorConfig {
+	return newOptionalConfig(map[string]config.MetricConfig{
+		"choke_early":   *config.NewMetricConfig("choke_early", "Choke early xstat", "choke"),
+		"choke_marked":  *config.NewMetricConfig("choke_marked", "Choke marked xstat", "choke"),
+		"choke_matched": *config.NewMetricConfig("choke_matched", "Choke matched xstat", "choke"),
+		"choke_other":   *config.NewMetricConfig("choke_other", "Choke other xstat", "choke"),
+		"choke_pdrop":   *config.NewMetricConfig("choke_pdrop", "Choke pdrop xstat", "choke"),
+	})
+}
@@ -11,28 +11,6 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// func init() {
-// 	mc := map[string]config.MetricConfig{
-// 		"bytes_total":      *config.NewMetricConfig("bytes_total", "QdiscPie byte counter", "qdisc"),
-// 		"packets_total":    *config.NewMetricConfig("packets_total", "QdiscPie packet counter", "qdisc"),
-// 		"drops_total":      *config.NewMetricConfig("drops_total", "QdiscPie queue drops", "qdisc"),
-// 		"overlimits_total": *config.NewMetricConfig("overlimits", "QdiscPie queue overlimits", "qdisc"),
-// 		"bps":              *config.NewMetricConfig("bps", "QdiscPie bytes per second", "qdisc"),
-// 		"pps":              *config.NewMetricConfig("pps", "QdiscPie packets per second", "qdisc"),
-// 		"qlen":             *config.NewMetricConfig("qlen", "QdiscPie current queue length", "qdisc"),
-// 		"backlog":          *config.NewMetricConfig("backlog", "QdiscPie current backlog in bytes", "qdisc"),
-// 		"requeues_total":   *config.NewMetricConfig("requeues_total", "QdiscPie number of requeues", "qdisc"),
-// 	}
-// 	qc := NewQdiscCollector(config.CollectorConfig{
-// 		Enabled:    true,
-// 		Timeout:    5,
-// 		RetryCount: 3,
-// 		Metrics:    mc},
-// 		logrus.StandardLogger(),
-// 	)
-// 	exporter.Register(qc)
-// }
-
 type QdiscCollector struct {
 	*base.QdiscBase
 }
@@ -62,10 +40,13 @@ func NewQdiscCollector(cfg config.CollectorConfig, logger *logrus.Logger) *Qdisc
 func (c *QdiscCollector) initializeMetrics(cfg *config.CollectorConfig) {
 	labelNames := c.LabelNames
 	for metricName, metricConfig := range cfg.GetMetrics() {
+		if !metricConfig.IsEnabled() {
+			continue
+		}
 		desc := prometheus.NewDesc(
 			"qdisc_"+metricName,
 			metricConfig.GetHelp(),
-			labelNames, nil,
+			labelNames, metricConfig.GetConstLabels(),
 		)
 		c.AddMetric(metricName, desc)
 		c.AddSupportedMetric(metricName)
@@ -0,0 +1,274 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+// Package sampler 提供高频队列采样收集器
+//
+// backlog/qlen 等指标是瞬时值，按抓取间隔读取会漏掉两次抓取之间的微突发。
+// 采样器在后台以较高频率（默认 100 Hz）读取选定设备的 qdisc 统计，
+// 并以直方图形式导出，从而可以回答“队列超过 1MB 的频率”一类问题。
+package sampler
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"gitee.com/openeuler/uos-tc-exporter/internal/metrics/base"
+	"gitee.com/openeuler/uos-tc-exporter/internal/metrics/config"
+	"gitee.com/openeuler/uos-tc-exporter/internal/tc"
+	tcerrors "gitee.com/openeuler/uos-tc-exporter/pkg/errors"
+	gotc "github.com/florianl/go-tc"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// deviceRefreshInterval 重新解析设备索引的间隔
+	deviceRefreshInterval = 30 * time.Second
+	// retryInterval 命名空间连接失败后的重试间隔
+	retryInterval = 5 * time.Second
+)
+
+var errNamespaceBackoff = errors.New("namespace is in retry backoff")
+
+// namespaceState 单个命名空间的采样状态，仅由采样 goroutine 访问
+type namespaceState struct {
+	sock        *gotc.Tc
+	devices     map[uint32]string
+	refreshedAt time.Time
+	retryAt     time.Time
+}
+
+// BacklogSampler 高频采样 qdisc 的 backlog/qlen/ldelay 并以直方图导出
+type BacklogSampler struct {
+	*base.CollectorBase
+	interval   time.Duration
+	targets    map[string][]string
+	histograms map[string]*prometheus.HistogramVec
+	namespaces map[string]*namespaceState
+	stopCh     chan struct{}
+	wg         sync.WaitGroup
+	startOnce  sync.Once
+	stopOnce   sync.Once
+}
+
+// NewBacklogSampler 创建高频采样收集器，cfg 需已通过 Validate
+func NewBacklogSampler(cfg config.SamplerConfig, logger *logrus.Logger) *BacklogSampler {
+	if logger == nil {
+		logger = logrus.StandardLogger()
+	}
+	collectorCfg := cfg.ToCollectorConfig()
+	s := &BacklogSampler{
+		CollectorBase: base.NewCollectorBase("sampler", "qdisc_sampler", "High-frequency qdisc backlog sampler", collectorCfg, logger),
+		interval:      cfg.Interval,
+		targets:       make(map[string][]string),
+		histograms:    make(map[string]*prometheus.HistogramVec),
+		namespaces:    make(map[string]*namespaceState),
+		stopCh:        make(chan struct{}),
+	}
+	if s.interval <= 0 {
+		s.interval = config.DefaultSamplerInterval
+	}
+	for _, target := range cfg.Targets {
+		ns := target.Namespace
+		if ns == "" {
+			ns = tc.DefaultNetNS
+		}
+		if target.Device == "" {
+			// 空设备表示采样该命名空间的所有设备
+			s.targets[ns] = nil
+			continue
+		}
+		if devices, exists := s.targets[ns]; exists && devices == nil {
+			continue
+		}
+		s.targets[ns] = append(s.targets[ns], target.Device)
+	}
+	for name, metricConfig := range collectorCfg.Metrics {
+		opts := prometheus.HistogramOpts{
+			Name:    "qdisc_sampled_" + name,
+			Help:    metricConfig.GetHelp(),
+			Buckets: metricConfig.GetBuckets(),
+		}
+		if cfg.NativeHistogramBucketFactor > 1 {
+			opts.NativeHistogramBucketFactor = cfg.NativeHistogramBucketFactor
+			opts.NativeHistogramMaxBucketNumber = 160
+			opts.NativeHistogramMinResetDuration = time.Hour
+		}
+		s.histograms[name] = prometheus.NewHistogramVec(opts, metricConfig.GetLabels())
+	}
+	s.SetCollectFunc(s.collect)
+	return s
+}
+
+// Start 启动后台采样
+func (s *BacklogSampler) Start() error {
+	s.startOnce.Do(func() {
+		s.Logger.Infof("Starting qdisc sampler with interval %v for %d namespaces", s.interval, len(s.targets))
+		s.wg.Add(1)
+		go s.run()
+	})
+	return nil
+}
+
+// Stop 停止后台采样并释放 netlink 连接
+func (s *BacklogSampler) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+		s.wg.Wait()
+		for ns := range s.namespaces {
+			s.closeNamespace(ns)
+		}
+		s.Logger.Info("Qdisc sampler stopped")
+	})
+}
+
+// collect 导出直方图
+func (s *BacklogSampler) collect(ch chan<- prometheus.Metric) {
+	for _, histogram := range s.histograms {
+		histogram.Collect(ch)
+	}
+}
+
+// run 采样循环
+func (s *BacklogSampler) run() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			s.sampleOnce()
+		}
+	}
+}
+
+// sampleOnce 对所有目标执行一次采样
+func (s *BacklogSampler) sampleOnce() {
+	for ns, devices := range s.targets {
+		state, err := s.namespaceState(ns, devices)
+		if err != nil {
+			if !errors.Is(err, errNamespaceBackoff) {
+				s.Logger.Warnf("Sampler failed to prepare netns %s, retry in %v: %v", ns, retryInterval, err)
+				s.SetLastError(tcerrors.Wrap(err, tcerrors.ErrCodeNetlinkOperation, "sampler failed to prepare netns").
+					WithContext("namespace", ns))
+			}
+			continue
+		}
+		qdiscs, err := state.sock.Qdisc().Get()
+		if err != nil {
+			s.Logger.Warnf("Sampler failed to dump qdiscs in netns %s: %v", ns, err)
+			s.SetLastError(tcerrors.Wrap(err, tcerrors.ErrCodeQdiscOperation, "sampler failed to dump qdiscs").
+				WithContext("namespace", ns))
+			s.closeNamespace(ns)
+			state.retryAt = time.Now().Add(retryInterval)
+			continue
+		}
+		for i := range qdiscs {
+			deviceName, ok := state.devices[qdiscs[i].Ifindex]
+			if !ok {
+				continue
+			}
+			s.observe(ns, deviceName, &qdiscs[i])
+		}
+	}
+}
+
+// namespaceState 获取命名空间的采样状态，必要时建立连接并刷新设备索引
+func (s *BacklogSampler) namespaceState(ns string, devices []string) (*namespaceState, error) {
+	state, ok := s.namespaces[ns]
+	if !ok {
+		state = &namespaceState{}
+		s.namespaces[ns] = state
+	}
+	now := time.Now()
+	if state.sock != nil && now.Sub(state.refreshedAt) < deviceRefreshInterval {
+		return state, nil
+	}
+	if state.sock == nil && now.Before(state.retryAt) {
+		return nil, errNamespaceBackoff
+	}
+
+	links, err := tc.GetInterfacesInNamespace(ns)
+	if err != nil {
+		s.closeNamespace(ns)
+		state.retryAt = now.Add(retryInterval)
+		return nil, err
+	}
+	wanted := make(map[string]bool, len(devices))
+	for _, device := range devices {
+		wanted[device] = true
+	}
+	state.devices = make(map[uint32]string)
+	for _, link := range links {
+		if link.Attributes == nil {
+			continue
+		}
+		if len(wanted) > 0 && !wanted[link.Attributes.Name] {
+			continue
+		}
+		state.devices[link.Index] = link.Attributes.Name
+	}
+	if len(wanted) > 0 && len(state.devices) < len(wanted) {
+		s.Logger.Debugf("Sampler found %d of %d configured devices in netns %s", len(state.devices), len(wanted), ns)
+	}
+	state.refreshedAt = now
+
+	if state.sock == nil {
+		sock, err := tc.GetTcConn(ns)
+		if err != nil {
+			state.retryAt = now.Add(retryInterval)
+			return nil, err
+		}
+		state.sock = sock
+	}
+	return state, nil
+}
+
+// closeNamespace 关闭命名空间的 netlink 连接
+func (s *BacklogSampler) closeNamespace(ns string) {
+	state, ok := s.namespaces[ns]
+	if !ok || state.sock == nil {
+		return
+	}
+	if err := state.sock.Close(); err != nil {
+		s.Logger.Debugf("Sampler failed to close tc socket in netns %s: %v", ns, err)
+	}
+	state.sock = nil
+}
+
+// observe 将一次采样结果写入直方图
+func (s *BacklogSampler) observe(ns, deviceName string, qdisc *gotc.Object) {
+	var backlog, qlen uint32
+	switch {
+	case qdisc.Stats2 != nil:
+		backlog, qlen = qdisc.Stats2.Backlog, qdisc.Stats2.Qlen
+	case qdisc.Stats != nil:
+		backlog, qlen = qdisc.Stats.Backlog, qdisc.Stats.Qlen
+	default:
+		return
+	}
+	labels := []string{ns, deviceName, qdisc.Kind, tc.FormatHandle(qdisc.Handle)}
+	s.histograms[config.SamplerMetricBacklog].WithLabelValues(labels...).Observe(float64(backlog))
+	s.histograms[config.SamplerMetricQlen].WithLabelValues(labels...).Observe(float64(qlen))
+	if delay, ok := queueDelay(qdisc); ok {
+		s.histograms[config.SamplerMetricLDelay].WithLabelValues(labels...).Observe(delay)
+	}
+}
+
+// queueDelay 从扩展统计中提取排队时延（秒），内核以微秒为单位上报
+func queueDelay(qdisc *gotc.Object) (float64, bool) {
+	if qdisc.XStats == nil {
+		return 0, false
+	}
+	switch {
+	case qdisc.XStats.Codel != nil:
+		return float64(qdisc.XStats.Codel.LDelay) / 1e6, true
+	case qdisc.XStats.Pie != nil:
+		return float64(qdisc.XStats.Pie.Delay) / 1e6, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package sampler
+
+import (
+	"testing"
+
+	"gitee.com/openeuler/uos-tc-exporter/internal/metrics/config"
+	gotc "github.com/florianl/go-tc"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestSamplerConfigBuckets(t *testing.T) {
+	tests := []struct {
+		name    string
+		buckets []float64
+		wantErr bool
+	}{
+		{name: "increasing", buckets: []float64{1024, 4096, 16384}},
+		{name: "duplicate bound", buckets: []float64{1024, 1024, 4096}, wantErr: true},
+		{name: "decreasing", buckets: []float64{4096, 1024}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.SamplerConfig{
+				Enabled: true,
+				Targets: []config.SamplerTarget{{Device: "eth0"}},
+				Buckets: map[string][]float64{config.SamplerMetricBacklog: tt.buckets},
+			}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBacklogSamplerObserve(t *testing.T) {
+	cfg := config.SamplerConfig{
+		Enabled: true,
+		Targets: []config.SamplerTarget{{Device: "eth0"}},
+		Buckets: map[string][]float64{config.SamplerMetricBacklog: {1024, 4096}},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	s := NewBacklogSampler(cfg, nil)
+
+	// 子直方图在第一次观测时创建，桶配置错误会在这里 panic
+	s.observe("default", "eth0", &gotc.Object{
+		Msg: gotc.Msg{Handle: 0x10000},
+		Attribute: gotc.Attribute{
+			Kind:   "fq_codel",
+			Stats2: &gotc.Stats2{Backlog: 2000, Qlen: 3},
+			XStats: &gotc.XStats{Codel: &gotc.CodelXStats{LDelay: 1500}},
+		},
+	})
+	s.observe("default", "eth0", &gotc.Object{Attribute: gotc.Attribute{Kind: "noqueue"}})
+
+	ch := make(chan prometheus.Metric, 10)
+	s.collect(ch)
+	close(ch)
+	counts := map[string]uint64{}
+	for metric := range ch {
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		counts[metric.Desc().String()] += m.GetHistogram().GetSampleCount()
+		if h := m.GetHistogram(); len(h.GetBucket()) == 2 && h.GetBucket()[0].GetCumulativeCount() != 0 {
+			t.Errorf("backlog 2000 counted in the 1024 bucket: %v", h.GetBucket())
+		}
+	}
+	if len(counts) != 3 {
+		t.Fatalf("got %d histograms, want backlog, qlen and ldelay", len(counts))
+	}
+	for desc, count := range counts {
+		if count != 1 {
+			t.Errorf("%s sample count = %d, want 1 (objects without stats are skipped)", desc, count)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var labelNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// CollectorSettings YAML 中单个收集器的配置，未设置的字段沿用收集器默认值
+type CollectorSettings struct {
+	Enabled *bool                     `yaml:"enabled"`
+	Timeout time.Duration             `yaml:"timeout"`
+	Retries *int                      `yaml:"retries"`
+	Metrics map[string]MetricSettings `yaml:"metrics"`
+}
+
+// MetricSettings YAML 中单个指标的配置
+type MetricSettings struct {
+	Enabled *bool `yaml:"enabled,omitempty"`
+	// Labels 附加到该指标上的固定标签
+	Labels map[string]string `yaml:"labels,omitempty"`
+}
+
+// MarshalYAML 按手写配置的格式输出，省略未设置的字段，超时输出为时长字符串
+func (cs CollectorSettings) MarshalYAML() (any, error) {
+	out := struct {
+		Enabled *bool                     `yaml:"enabled,omitempty"`
+		Timeout string                    `yaml:"timeout,omitempty"`
+		Retries *int                      `yaml:"retries,omitempty"`
+		Metrics map[string]MetricSettings `yaml:"metrics,omitempty"`
+	}{Enabled: cs.Enabled, Retries: cs.Retries, Metrics: cs.Metrics}
+	if cs.Timeout > 0 {
+		out.Timeout = cs.Timeout.String()
+	}
+	return out, nil
+}
+
+// Merge 返回在 cs 之上叠加 patch 中已设置字段的新配置，不修改 cs
+func (cs CollectorSettings) Merge(patch CollectorSettings) CollectorSettings {
+	merged := cs
+	if patch.Enabled != nil {
+		merged.Enabled = patch.Enabled
+	}
+	if patch.Timeout != 0 {
+		merged.Timeout = patch.Timeout
+	}
+	if patch.Retries != nil {
+		merged.Retries = patch.Retries
+	}
+	if len(patch.Metrics) > 0 {
+		merged.Metrics = make(map[string]MetricSettings, len(cs.Metrics)+len(patch.Metrics))
+		for name, metric := range cs.Metrics {
+			merged.Metrics[name] = metric
+		}
+		for name, metric := range patch.Metrics {
+			current := merged.Metrics[name]
+			if metric.Enabled != nil {
+				current.Enabled = metric.Enabled
+			}
+			if metric.Labels != nil {
+				current.Labels = metric.Labels
+			}
+			merged.Metrics[name] = current
+		}
+	}
+	return merged
+}
+
+// CollectorsConfig collectors 配置段，键为收集器 ID
+type CollectorsConfig map[string]CollectorSettings
+
+var (
+	defaultsMu sync.RWMutex
+	// defaults 各收集器的默认配置构造函数，键为收集器 ID
+	defaults = make(map[string]func() *CollectorConfig)
+)
+
+// RegisterDefaults 注册收集器的默认配置，由各收集器包在 init 中调用
+func RegisterDefaults(id string, fn func() *CollectorConfig) {
+	defaultsMu.Lock()
+	defer defaultsMu.Unlock()
+	defaults[id] = fn
+}
+
+// KnownCollectors 返回已注册默认配置的收集器 ID
+func KnownCollectors() []string {
+	defaultsMu.RLock()
+	defer defaultsMu.RUnlock()
+	ids := make([]string, 0, len(defaults))
+	for id := range defaults {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// DefaultsFor 返回收集器默认配置的新副本
+func DefaultsFor(id string) (*CollectorConfig, bool) {
+	defaultsMu.RLock()
+	fn, ok := defaults[id]
+	defaultsMu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return fn(), true
+}
+
+// Validate 检查收集器和指标名称是否已知以及取值是否合法
+func (cc CollectorsConfig) Validate() error {
+	var errs []string
+	for _, id := range cc.sortedIDs() {
+		if _, err := cc.Resolve(id); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Resolve 返回收集器的最终配置：默认配置叠加 YAML 中的覆盖项
+func (cc CollectorsConfig) Resolve(id string) (*CollectorConfig, error) {
+	cfg, ok := DefaultsFor(id)
+	if !ok {
+		return nil, fmt.Errorf("unknown collector %q, known collectors: %s", id, strings.Join(KnownCollectors(), ", "))
+	}
+	settings, ok := cc[id]
+	if !ok {
+		return cfg, nil
+	}
+
+	if settings.Enabled != nil {
+		cfg.SetEnabled(*settings.Enabled)
+	}
+	if settings.Timeout < 0 {
+		return nil, fmt.Errorf("collector %q: timeout cannot be negative, got %v", id, settings.Timeout)
+	}
+	if settings.Timeout > 0 {
+		cfg.SetTimeout(settings.Timeout)
+	}
+	if settings.Retries != nil {
+		if *settings.Retries < 0 {
+			return nil, fmt.Errorf("collector %q: retries cannot be negative, got %d", id, *settings.Retries)
+		}
+		cfg.SetRetryCount(*settings.Retries)
+	}
+
+	metricNames := make([]string, 0, len(settings.Metrics))
+	for name := range settings.Metrics {
+		metricNames = append(metricNames, name)
+	}
+	sort.Strings(metricNames)
+	for _, name := range metricNames {
+		metric, ok := cfg.Metrics[name]
+		if !ok {
+			return nil, fmt.Errorf("collector %q: unknown metric %q", id, name)
+		}
+		override := settings.Metrics[name]
+		if override.Enabled != nil {
+			metric.SetEnabled(*override.Enabled)
+		}
+		if len(override.Labels) > 0 {
+			if err := validateConstLabels(override.Labels, metric.GetLabels()); err != nil {
+				return nil, fmt.Errorf("collector %q metric %q: %w", id, name, err)
+			}
+			metric.SetConstLabels(override.Labels)
+		}
+		cfg.Metrics[name] = metric
+	}
+	return cfg, nil
+}
+
+func (cc CollectorsConfig) sortedIDs() []string {
+	ids := make([]string, 0, len(cc))
+	for id := range cc {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// validateConstLabels 检查固定标签名合法且不与指标的可变标签冲突
+func validateConstLabels(labels map[string]string, variable []string) error {
+	for name := range labels {
+		if !labelNameRegex.MatchString(name) || strings.HasPrefix(name, "__") {
+			return fmt.Errorf("invalid label name %q", name)
+		}
+		for _, v := range variable {
+			if name == v {
+				return fmt.Errorf("label %q conflicts with a built-in label", name)
+			}
+		}
+	}
+	return nil
+}
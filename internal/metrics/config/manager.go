@@ -5,9 +5,16 @@ package config
 
 import "time"
 
+// ManagerConfig 指标管理器配置，对应配置文件的 monitoring 段
 type ManagerConfig struct {
+	// Enabled 是否输出导出器自身指标和应用信息
+	Enabled               bool          `yaml:"enabled"`
 	PerformanceMonitoring bool          `yaml:"performance_monitoring"`
 	CollectionInterval    time.Duration `yaml:"collection_interval"`
 	StatsRetention        time.Duration `yaml:"stats_retention"`
 	EnableBusinessMetrics bool          `yaml:"enable_business_metrics"`
+	// MinCollectInterval 两次实际收集的最小间隔，间隔内的抓取复用上一次结果，0 表示每次抓取都收集
+	MinCollectInterval time.Duration `yaml:"min_collect_interval"`
+	// Collectors 收集器配置，来自配置文件的 collectors 段
+	Collectors CollectorsConfig `yaml:"-"`
 }
@@ -11,6 +11,8 @@ type MetricConfig struct {
 	mtype   string
 	labels  []string
 	buckets []float64
+	// constLabels 附加到指标上的固定标签，来自 collectors 配置段
+	constLabels map[string]string
 }
 
 // NewMetricConfig 创建指标配置
@@ -59,7 +61,22 @@ func (mc *MetricConfig) SetLabels(labels []string) {
 	mc.labels = labels
 }
 
+// GetBuckets 获取桶配置（用于直方图）
+func (mc *MetricConfig) GetBuckets() []float64 {
+	return mc.buckets
+}
+
 // SetBuckets 设置桶配置（用于直方图）
 func (mc *MetricConfig) SetBuckets(buckets []float64) {
 	mc.buckets = buckets
 }
+
+// GetConstLabels 获取固定标签
+func (mc *MetricConfig) GetConstLabels() map[string]string {
+	return mc.constLabels
+}
+
+// SetConstLabels 设置固定标签
+func (mc *MetricConfig) SetConstLabels(labels map[string]string) {
+	mc.constLabels = labels
+}
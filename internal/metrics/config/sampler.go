@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// SamplerMetricBacklog 采样的队列积压字节数
+	SamplerMetricBacklog = "backlog_bytes"
+	// SamplerMetricQlen 采样的队列长度（包数）
+	SamplerMetricQlen = "qlen"
+	// SamplerMetricLDelay 采样的排队时延（仅 codel/pie 提供）
+	SamplerMetricLDelay = "ldelay_seconds"
+
+	// DefaultSamplerInterval 默认采样间隔（100 Hz）
+	DefaultSamplerInterval = 10 * time.Millisecond
+	// MinSamplerInterval 允许的最小采样间隔
+	MinSamplerInterval = time.Millisecond
+)
+
+var (
+	// samplerMetricHelp 采样指标的帮助信息
+	samplerMetricHelp = map[string]string{
+		SamplerMetricBacklog: "Distribution of the qdisc backlog in bytes sampled between scrapes",
+		SamplerMetricQlen:    "Distribution of the qdisc queue length in packets sampled between scrapes",
+		SamplerMetricLDelay:  "Distribution of the qdisc queueing delay in seconds sampled between scrapes",
+	}
+	// defaultSamplerBuckets 默认的经典直方图桶（1KB~16MB、1~16384 包、100us~1.6s）
+	defaultSamplerBuckets = map[string][]float64{
+		SamplerMetricBacklog: {1024, 4096, 16384, 65536, 262144, 1048576, 4194304, 16777216},
+		SamplerMetricQlen:    {1, 4, 16, 64, 256, 1024, 4096, 16384},
+		SamplerMetricLDelay:  {0.0001, 0.0004, 0.0016, 0.0064, 0.0256, 0.1024, 0.4096, 1.6384},
+	}
+)
+
+// SamplerTarget 采样目标，Device 为空时采样命名空间内的所有设备
+type SamplerTarget struct {
+	Namespace string `yaml:"namespace"`
+	Device    string `yaml:"device"`
+}
+
+// SamplerConfig 高频队列采样配置
+type SamplerConfig struct {
+	Enabled  bool            `yaml:"enabled"`
+	Interval time.Duration   `yaml:"interval"`
+	Targets  []SamplerTarget `yaml:"targets"`
+	// Buckets 按指标名覆盖经典直方图的桶边界，显式配置为空列表时不输出经典桶
+	Buckets map[string][]float64 `yaml:"buckets"`
+	// NativeHistogramBucketFactor 大于 1 时同时输出 Prometheus 原生直方图
+	NativeHistogramBucketFactor float64 `yaml:"native_histogram_bucket_factor"`
+}
+
+// SamplerMetricNames 返回采样器支持的指标名
+func SamplerMetricNames() []string {
+	return []string{SamplerMetricBacklog, SamplerMetricQlen, SamplerMetricLDelay}
+}
+
+// Validate 验证采样配置并填充默认值
+func (sc *SamplerConfig) Validate() error {
+	if !sc.Enabled {
+		return nil
+	}
+	if sc.Interval == 0 {
+		sc.Interval = DefaultSamplerInterval
+	}
+	if sc.Interval < MinSamplerInterval {
+		return fmt.Errorf("sampler interval must be at least %v, got %v", MinSamplerInterval, sc.Interval)
+	}
+	if len(sc.Targets) == 0 {
+		return fmt.Errorf("sampler is enabled but no targets are configured")
+	}
+	for i := range sc.Targets {
+		if sc.Targets[i].Namespace == "" {
+			sc.Targets[i].Namespace = "default"
+		}
+	}
+	if sc.NativeHistogramBucketFactor != 0 && sc.NativeHistogramBucketFactor <= 1 {
+		return fmt.Errorf("native histogram bucket factor must be greater than 1, got %v", sc.NativeHistogramBucketFactor)
+	}
+	for name, buckets := range sc.Buckets {
+		if _, ok := samplerMetricHelp[name]; !ok {
+			return fmt.Errorf("unknown sampler metric %q in buckets, supported metrics are: %v", name, SamplerMetricNames())
+		}
+		// client_golang 要求桶边界严格递增，否则在第一次创建子直方图时 panic
+		for i := 0; i+1 < len(buckets); i++ {
+			if !(buckets[i] < buckets[i+1]) {
+				return fmt.Errorf("buckets of sampler metric %q must be strictly increasing, got %v", name, buckets)
+			}
+		}
+		if len(buckets) == 0 && sc.NativeHistogramBucketFactor == 0 {
+			return fmt.Errorf("sampler metric %q has no buckets and native histograms are disabled", name)
+		}
+	}
+	return nil
+}
+
+// ToCollectorConfig 转换为收集器配置，桶边界保存在各指标的 MetricConfig 中
+func (sc *SamplerConfig) ToCollectorConfig() *CollectorConfig {
+	cfg := NewCollectorConfig()
+	cfg.Enabled = sc.Enabled
+	cfg.Timeout = sc.Interval
+	for _, name := range SamplerMetricNames() {
+		mc := NewMetricConfig(name, samplerMetricHelp[name], "histogram")
+		mc.SetLabels([]string{"namespace", "device", "kind", "handle"})
+		if buckets, ok := sc.Buckets[name]; ok {
+			mc.SetBuckets(buckets)
+		} else {
+			mc.SetBuckets(defaultSamplerBuckets[name])
+		}
+		cfg.AddMetric(name, *mc)
+	}
+	return cfg
+}
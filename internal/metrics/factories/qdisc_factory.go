@@ -34,11 +34,14 @@ func (qf *QdiscFactory) RemoveConfig(qdiscType string) {
 	delete(qf.configs, qdiscType)
 }
 
+// GetSupportedTypes 返回可以创建收集器的 qdisc 类型
 func (qf *QdiscFactory) GetSupportedTypes() []string {
-	return []string{
-		"codel", "cbq", "htb", "fq", "fq_codel",
-		"choke", "pie", "red", "sfb", "sfq", "hfsc",
-	}
+	return []string{"qdisc", "codel", "cbq", "choke"}
+}
+
+// CollectorID 返回 qdisc 类型对应的收集器 ID，与收集器的 ID() 一致
+func CollectorID(qdiscType string) string {
+	return "qdisc_" + qdiscType
 }
 
 func (qf *QdiscFactory) CreateCollector(qdiscType string) (interfaces.MetricCollector, error) {
@@ -52,6 +55,10 @@ func (qf *QdiscFactory) CreateCollector(qdiscType string) (interfaces.MetricColl
 	switch qdiscType {
 	case "codel":
 		return qdisc.NewCodelCollector(*cfg, logger), nil
+	case "cbq":
+		return qdisc.NewCbqCollector(*cfg, logger), nil
+	case "choke":
+		return qdisc.NewChokeCollector(*cfg, logger), nil
 	case "qdisc":
 		return qdisc.NewQdiscCollector(*cfg, logger), nil
 	default:
@@ -3,7 +3,11 @@
 
 package interfaces
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
 
 type MetricCollector interface {
 	Identifiable
@@ -29,3 +33,25 @@ type Collectible interface {
 	Enabled() bool
 	SetEnabled(enabled bool)
 }
+
+// Runnable 需要在后台持续运行的收集器（例如高频采样器）
+type Runnable interface {
+	Start() error
+	Stop()
+}
+
+// ErrorReporter 可上报最近一次采集错误的收集器
+type ErrorReporter interface {
+	GetLastError() error
+	SetLastError(err error)
+}
+
+// CollectTimeReporter 可上报最近一次采集时间的收集器
+type CollectTimeReporter interface {
+	GetLastCollectTime() time.Time
+}
+
+// ScopedCollector 支持只收集单个命名空间（及可选设备）的收集器，供 /probe 使用
+type ScopedCollector interface {
+	CollectScope(ch chan<- prometheus.Metric, namespace, device string)
+}
@@ -48,4 +48,7 @@ type MetricConfig interface {
 
 	// GetLabels 获取标签列表
 	GetLabels() []string
+
+	// GetConstLabels 获取固定标签
+	GetConstLabels() map[string]string
 }
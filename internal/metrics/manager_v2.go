@@ -4,16 +4,31 @@
 package metrics
 
 import (
+	stderrors "errors"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
+	"gitee.com/openeuler/uos-tc-exporter/internal/metrics/collectors/business"
 	"gitee.com/openeuler/uos-tc-exporter/internal/metrics/config"
 	"gitee.com/openeuler/uos-tc-exporter/internal/metrics/factories"
 	"gitee.com/openeuler/uos-tc-exporter/internal/metrics/interfaces"
 	"gitee.com/openeuler/uos-tc-exporter/internal/metrics/registry"
+	"gitee.com/openeuler/uos-tc-exporter/internal/tc"
+	"gitee.com/openeuler/uos-tc-exporter/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
+)
+
+// 收集请求的结果来源，作为 tc_exporter_scrapes_total 的 source 标签
+const (
+	scrapeCollected = "collected"
+	scrapeShared    = "shared"
+	scrapeCached    = "cached"
 )
 
 type ManagerV2 struct {
@@ -21,8 +36,42 @@ type ManagerV2 struct {
 	factories map[string]registry.CollectorFactory
 	config    *config.ManagerConfig
 	stats     *CollectionStats
+	self      *SelfMetrics
 	logger    *logrus.Logger
-	// Add fields as necessary
+	// group 合并并发的收集请求
+	group singleflight.Group
+	// cacheMu 保护 cached
+	cacheMu sync.Mutex
+	// cached 按收集器选择缓存的最近一次收集结果，用于 MinCollectInterval
+	cached map[string]*collection
+	// overridesMu 保护 overrides
+	overridesMu sync.Mutex
+	// overrides 通过管理 API 在运行时修改的收集器配置，热重载重建管理器后失效
+	overrides map[string]config.CollectorSettings
+	// healthMu 保护 health 和 running
+	healthMu sync.Mutex
+	health   CollectionHealth
+	// running 正在进行的完整收集数
+	running int
+	// abandoned 超时后被放弃但仍未返回的收集器 ID
+	abandoned sync.Map
+}
+
+// CollectionHealth 完整收集（/metrics 抓取触发的收集）的健康状况，供健康检查使用
+type CollectionHealth struct {
+	// ConsecutiveFailures 最近连续失败的收集次数，任一收集器出错即视为失败
+	ConsecutiveFailures int
+	LastSuccess         time.Time
+	LastAttempt         time.Time
+	LastError           error
+	// RunningSince 进行中的收集开始的时间，并发收集时取第一个开始的，全部结束后为零值
+	RunningSince time.Time
+}
+
+// collection 一次完整收集输出的指标，可被多个抓取共享
+type collection struct {
+	metrics  []prometheus.Metric
+	finished time.Time
 }
 
 type CollectionStats struct {
@@ -38,8 +87,52 @@ type CollectionStats struct {
 	LastError             error
 }
 
+// RecordCollection 记录一次完整收集的结果
+func (cs *CollectionStats) RecordCollection(duration time.Duration, success bool, err error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.TotalCollections++
+	cs.TotalDuration += duration
+	cs.AverageDuration = cs.TotalDuration / time.Duration(cs.TotalCollections)
+	cs.LastCollectionTime = time.Now()
+	if success {
+		cs.SuccessfulCollections++
+		return
+	}
+	cs.FailedCollections++
+	cs.LastErrorTime = cs.LastCollectionTime
+	cs.LastError = err
+}
+
+// CollectorState 收集器运行状态快照
+type CollectorState struct {
+	ID               string    `json:"id"`
+	Name             string    `json:"name"`
+	Description      string    `json:"description"`
+	Enabled          bool      `json:"enabled"`
+	SupportedMetrics []string  `json:"supported_metrics,omitempty"`
+	Timeout          string    `json:"timeout,omitempty"`
+	Retries          int       `json:"retries"`
+	LastError        string    `json:"last_error,omitempty"`
+	LastCollect      time.Time `json:"last_collect"`
+}
+
+// String 返回统计信息摘要
+func (cs *CollectionStats) String() string {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	summary := fmt.Sprintf("total=%d successful=%d failed=%d average=%v last=%s",
+		cs.TotalCollections, cs.SuccessfulCollections, cs.FailedCollections,
+		cs.AverageDuration, cs.LastCollectionTime.Format(time.RFC3339))
+	if cs.LastError != nil {
+		summary += fmt.Sprintf(" last_error=%q at %s", cs.LastError.Error(), cs.LastErrorTime.Format(time.RFC3339))
+	}
+	return summary
+}
+
 func NewManagerV2(cfg *config.ManagerConfig, logger *logrus.Logger) *ManagerV2 {
 	defaultCfg := config.ManagerConfig{
+		Enabled:               true,
 		PerformanceMonitoring: true,
 		CollectionInterval:    30 * time.Second,
 		StatsRetention:        24 * time.Hour,
@@ -56,8 +149,12 @@ func NewManagerV2(cfg *config.ManagerConfig, logger *logrus.Logger) *ManagerV2 {
 		factories: make(map[string]registry.CollectorFactory),
 		config:    cfg,
 		stats:     &CollectionStats{},
+		self:      NewSelfMetrics(),
 		logger:    logger,
+		cached:    make(map[string]*collection),
+		overrides: make(map[string]config.CollectorSettings),
 	}
+	m.AttachNetlinkObserver()
 	// Additional initialization logic can be added here
 	m.initializeFactories()
 	m.registerCollectors()
@@ -68,20 +165,14 @@ func (m *ManagerV2) initializeFactories() {
 	// Initialize and register different factories
 	m.logger.Info("Initializing Qdisc Factory")
 	qdiscFactory := factories.NewQdiscFactory()
-	mc := map[string]config.MetricConfig{
-		"bytes_total":      *config.NewMetricConfig("bytes_total", "QdiscPie byte counter", "qdisc"),
-		"packets_total":    *config.NewMetricConfig("packets_total", "QdiscPie packet counter", "qdisc"),
-		"drops_total":      *config.NewMetricConfig("drops_total", "QdiscPie queue drops", "qdisc"),
-		"overlimits_total": *config.NewMetricConfig("overlimits", "QdiscPie queue overlimits", "qdisc"),
-		"bps":              *config.NewMetricConfig("bps", "QdiscPie bytes per second", "qdisc"),
-		"pps":              *config.NewMetricConfig("pps", "QdiscPie packets per second", "qdisc"),
-		"qlen":             *config.NewMetricConfig("qlen", "QdiscPie current queue length", "qdisc"),
-		"backlog":          *config.NewMetricConfig("backlog", "QdiscPie current backlog in bytes", "qdisc"),
-		"requeues_total":   *config.NewMetricConfig("requeues_total", "QdiscPie number of requeues", "qdisc"),
-	}
-	cfg := config.NewCollectorConfig()
-	cfg.Metrics = mc
-	qdiscFactory.AddConfig("qdisc", cfg)
+	for _, qdiscType := range qdiscFactory.GetSupportedTypes() {
+		cfg, err := m.ResolveCollectorConfig(factories.CollectorID(qdiscType))
+		if err != nil {
+			m.logger.Warnf("Failed to resolve config for qdisc collector %s: %v", qdiscType, err)
+			continue
+		}
+		qdiscFactory.AddConfig(qdiscType, cfg)
+	}
 	m.factories["qdisc"] = qdiscFactory
 	m.registry.RegisterFactory("qdisc", qdiscFactory)
 	// Add other factories as needed
@@ -89,8 +180,16 @@ func (m *ManagerV2) initializeFactories() {
 
 func (m *ManagerV2) registerCollectors() {
 	// 注册 qdisc 收集器
-	qdiscTypes := []string{"codel", "cbq", "htb", "fq", "fq_codel", "choke", "pie", "red", "sfb", "sfq", "hfsc", "qdisc"}
-	for _, qdiscType := range qdiscTypes {
+	qdiscFactory := m.factories["qdisc"].(*factories.QdiscFactory)
+	for _, qdiscType := range qdiscFactory.GetSupportedTypes() {
+		cfg, exists := qdiscFactory.GetConfig(qdiscType)
+		if !exists {
+			continue
+		}
+		if !cfg.IsEnabled() {
+			m.logger.Infof("Qdisc collector %s disabled by config", qdiscType)
+			continue
+		}
 		collector, err := m.registry.CreateCollector("qdisc", qdiscType)
 		if err == nil {
 			m.registry.Register(collector)
@@ -98,6 +197,26 @@ func (m *ManagerV2) registerCollectors() {
 			m.logger.Warnf("Failed to create qdisc collector %s: %v", qdiscType, err)
 		}
 	}
+
+	// 注册业务派生指标收集器
+	if m.config.EnableBusinessMetrics {
+		cfg, err := m.ResolveCollectorConfig("business")
+		if err != nil {
+			m.logger.Warnf("Failed to resolve config for business collector: %v", err)
+		} else if cfg.IsEnabled() {
+			// 速率窗口不短于 MinCollectInterval，缓存期内的抓取与窗口一致
+			window := business.DefaultMinRateWindow
+			if m.config.MinCollectInterval > window {
+				window = m.config.MinCollectInterval
+			}
+			m.registry.Register(business.NewBusinessCollector(*cfg, window, m.logger))
+		}
+	}
+}
+
+// ResolveCollectorConfig 返回收集器的最终配置：默认配置叠加 collectors 配置段
+func (m *ManagerV2) ResolveCollectorConfig(id string) (*config.CollectorConfig, error) {
+	return m.config.Collectors.Resolve(id)
 }
 
 func (m *ManagerV2) GetStats() *CollectionStats {
@@ -106,24 +225,517 @@ func (m *ManagerV2) GetStats() *CollectionStats {
 	// fix: returning a copy of stats can lead to issues with the embedded RWMutex
 	return m.stats
 }
+
+// AttachNetlinkObserver 将 netlink 调用耗时上报到本管理器的自身指标
+// netlink 观察者是全局的，热重载回滚到旧管理器时需要重新调用
+func (m *ManagerV2) AttachNetlinkObserver() {
+	tc.SetLatencyObserver(m.self.ObserveNetlink)
+}
+
+// SelfMetrics 返回导出器自身指标，需注册到 Prometheus 注册表
+func (m *ManagerV2) SelfMetrics() *SelfMetrics {
+	return m.self
+}
+
 func (m *ManagerV2) Shutdown() {
 	m.logger.Info("Shutting down ManagerV2")
+	for _, collector := range m.registry.GetAllCollectors() {
+		if runnable, ok := collector.(interfaces.Runnable); ok {
+			runnable.Stop()
+		}
+	}
+}
+
+// RegisterCollector 注册额外的收集器，需要后台运行的收集器会被启动
+func (m *ManagerV2) RegisterCollector(collector interfaces.MetricCollector) error {
+	if err := m.registry.Register(collector); err != nil {
+		return err
+	}
+	runnable, ok := collector.(interfaces.Runnable)
+	if !ok {
+		return nil
+	}
+	if err := runnable.Start(); err != nil {
+		m.registry.Unregister(collector.ID())
+		return fmt.Errorf("failed to start collector %s: %w", collector.ID(), err)
+	}
+	return nil
 }
 
 // CollectAll 收集所有指标
+// 并发到达的调用共享同一次收集；配置了 MinCollectInterval 时，间隔内的调用直接复用上一次结果
 func (m *ManagerV2) CollectAll(ch chan<- prometheus.Metric) {
-	start := time.Now()
+	m.emit(ch, m.sharedCollection("", m.registry.GetEnableCollectors()))
+}
+
+// CollectSelected 只收集 include 中的收集器（为空表示全部启用的收集器），并跳过 exclude 中的收集器
+// 未知或未启用的收集器 ID 返回错误，此时不运行任何收集器
+func (m *ManagerV2) CollectSelected(ch chan<- prometheus.Metric, include, exclude []string) error {
+	collectors, err := m.SelectCollectors(include, exclude)
+	if err != nil {
+		return err
+	}
+	m.emit(ch, m.sharedCollection(selectionKey(collectors), collectors))
+	return nil
+}
+
+// CollectScope 只收集指定命名空间（及可选设备）的指标，仅运行实现了 interfaces.ScopedCollector 的启用收集器
+// 探测请求不参与抓取合并，也不计入收集器自身指标，返回最后一个收集器错误
+func (m *ManagerV2) CollectScope(ch chan<- prometheus.Metric, namespace, device string) error {
+	if err := checkScope(namespace, device); err != nil {
+		return err
+	}
+	var lastErr error
+	for _, collector := range m.registry.GetEnableCollectors() {
+		scoped, ok := collector.(interfaces.ScopedCollector)
+		if !ok {
+			continue
+		}
+		if err := m.collectScopeOne(collector, scoped, ch, namespace, device); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// checkScope 确认命名空间存在，且 device 非空时该设备存在于命名空间中
+func checkScope(namespace, device string) error {
+	if !tc.ValidateNamespace(namespace) {
+		return errors.New(errors.ErrCodeTCOperation, "network namespace not found").
+			WithContext("namespace", namespace)
+	}
+	if device == "" {
+		return nil
+	}
+	devices, err := tc.GetInterfacesInNamespace(namespace)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeNetlinkOperation, "get interfaces failed").
+			WithContext("namespace", namespace)
+	}
+	for _, link := range devices {
+		if link.Attributes != nil && link.Attributes.Name == device {
+			return nil
+		}
+	}
+	return errors.New(errors.ErrCodeNetlinkOperation, "device not found in network namespace").
+		WithContext("namespace", namespace).WithContext("device", device)
+}
+
+// collectScopeOne 运行单个收集器的范围收集，捕获 panic 并返回收集器上报的错误
+func (m *ManagerV2) collectScopeOne(collector interfaces.MetricCollector, scoped interfaces.ScopedCollector,
+	ch chan<- prometheus.Metric, namespace, device string) (err error) {
+	reporter, hasReporter := collector.(interfaces.ErrorReporter)
+	if hasReporter {
+		reporter.SetLastError(nil)
+	}
 	defer func() {
-		duration := time.Since(start)
-		// m.stats.RecordCollection(duration, true, nil)
-		fmt.Printf("Collection took %v\n", duration)
+		if rec := recover(); rec != nil {
+			err = errors.New(errors.ErrCodeMetricsCollect, fmt.Sprintf("collector panic: %v", rec)).
+				WithContext("collector", collector.ID()).WithContext("namespace", namespace)
+			m.logger.Errorf("Collector %s panicked during probe: %v", collector.ID(), rec)
+			return
+		}
+		if hasReporter {
+			err = reporter.GetLastError()
+		}
+	}()
+	scoped.CollectScope(ch, namespace, device)
+	return nil
+}
+
+// SelectCollectors 按 ID 选择启用的收集器，结果按 ID 排序
+func (m *ManagerV2) SelectCollectors(include, exclude []string) ([]interfaces.MetricCollector, error) {
+	enabled := make(map[string]interfaces.MetricCollector)
+	for _, collector := range m.registry.GetEnableCollectors() {
+		enabled[collector.ID()] = collector
+	}
+	check := func(id string) error {
+		if _, ok := enabled[id]; ok {
+			return nil
+		}
+		if _, exists := m.registry.GetCollector(id); exists {
+			return fmt.Errorf("collector %s is disabled", id)
+		}
+		return fmt.Errorf("collector %s not found", id)
+	}
+
+	// selected 不能与 enabled 共用同一个 map，否则排除后重复的 exclude 会被判为已禁用
+	selected := make(map[string]interfaces.MetricCollector)
+	if len(include) == 0 {
+		for id, collector := range enabled {
+			selected[id] = collector
+		}
+	}
+	for _, id := range include {
+		if err := check(id); err != nil {
+			return nil, err
+		}
+		selected[id] = enabled[id]
+	}
+	for _, id := range exclude {
+		if err := check(id); err != nil {
+			return nil, err
+		}
+		delete(selected, id)
+	}
+
+	collectors := make([]interfaces.MetricCollector, 0, len(selected))
+	for _, collector := range selected {
+		collectors = append(collectors, collector)
+	}
+	sort.Slice(collectors, func(i, j int) bool { return collectors[i].ID() < collectors[j].ID() })
+	return collectors, nil
+}
+
+// selectionKey 返回收集器选择的合并键，空字符串表示全部启用的收集器
+func selectionKey(collectors []interfaces.MetricCollector) string {
+	ids := make([]string, len(collectors))
+	for i, collector := range collectors {
+		ids[i] = collector.ID()
+	}
+	return "selected:" + strings.Join(ids, ",")
+}
+
+// emit 将收集结果输出到 ch
+func (m *ManagerV2) emit(ch chan<- prometheus.Metric, result *collection) {
+	for _, metric := range result.metrics {
+		ch <- metric
+	}
+}
+
+// sharedCollection 返回 key 对应的可复用的最近结果，或加入/发起一次收集
+func (m *ManagerV2) sharedCollection(key string, collectors []interfaces.MetricCollector) *collection {
+	interval := m.config.MinCollectInterval
+	if interval > 0 {
+		m.cacheMu.Lock()
+		cached := m.cached[key]
+		m.cacheMu.Unlock()
+		if cached != nil && time.Since(cached.finished) < interval {
+			m.self.ObserveScrape(scrapeCached)
+			return cached
+		}
+	}
+
+	leader := false
+	v, _, _ := m.group.Do(key, func() (any, error) {
+		leader = true
+		result := m.collect(collectors)
+		if interval > 0 {
+			m.cacheMu.Lock()
+			for k, c := range m.cached {
+				if time.Since(c.finished) >= interval {
+					delete(m.cached, k)
+				}
+			}
+			m.cached[key] = result
+			m.cacheMu.Unlock()
+		}
+		return result, nil
+	})
+	if leader {
+		m.self.ObserveScrape(scrapeCollected)
+	} else {
+		m.self.ObserveScrape(scrapeShared)
+	}
+	return v.(*collection)
+}
+
+// collect 运行给定的收集器并缓存输出
+func (m *ManagerV2) collect(collectors []interfaces.MetricCollector) *collection {
+	ch := make(chan prometheus.Metric, 256)
+	result := &collection{}
+	done := make(chan struct{})
+	go func() {
+		for metric := range ch {
+			result.metrics = append(result.metrics, metric)
+		}
+		close(done)
 	}()
-	collectors := m.registry.GetEnableCollectors()
+	m.collectFrom(collectors, ch)
+	close(ch)
+	<-done
+	result.finished = time.Now()
+	return result
+}
+
+// collectFrom 依次运行给定的收集器
+func (m *ManagerV2) collectFrom(collectors []interfaces.MetricCollector, ch chan<- prometheus.Metric) {
+	start := time.Now()
+	m.beginCollection(start)
+	var lastErr error
 	for _, collector := range collectors {
-		fmt.Println("Collecting from collector:", collector.ID())
-		collector.Collect(ch)
+		if err := m.collectWithRetry(collector, ch); err != nil {
+			lastErr = err
+		}
 	}
+	m.endCollection(lastErr)
+	duration := time.Since(start)
+	if m.config.PerformanceMonitoring {
+		m.stats.RecordCollection(duration, lastErr == nil, lastErr)
+	}
+	m.logger.Debugf("Collection from %d collectors took %v", len(collectors), duration)
+}
+
+// beginCollection 记录一次完整收集开始
+func (m *ManagerV2) beginCollection(start time.Time) {
+	m.healthMu.Lock()
+	defer m.healthMu.Unlock()
+	if m.running == 0 {
+		m.health.RunningSince = start
+	}
+	m.running++
+}
 
+// endCollection 记录一次完整收集的结果
+func (m *ManagerV2) endCollection(err error) {
+	m.healthMu.Lock()
+	defer m.healthMu.Unlock()
+	m.running--
+	if m.running == 0 {
+		m.health.RunningSince = time.Time{}
+	}
+	m.health.LastAttempt = time.Now()
+	if err != nil {
+		m.health.ConsecutiveFailures++
+		m.health.LastError = err
+		return
+	}
+	m.health.ConsecutiveFailures = 0
+	m.health.LastSuccess = m.health.LastAttempt
+}
+
+// CollectionHealth 返回完整收集的健康状况快照
+func (m *ManagerV2) CollectionHealth() CollectionHealth {
+	m.healthMu.Lock()
+	defer m.healthMu.Unlock()
+	return m.health
+}
+
+// collectRetryBackoff 收集器重试前的初始等待时间，每次重试翻倍
+const collectRetryBackoff = 100 * time.Millisecond
+
+// collectWithRetry 按收集器配置的重试次数和超时运行收集器
+// 仅在失败且未输出任何序列时重试，避免向同一次抓取输出重复序列
+func (m *ManagerV2) collectWithRetry(collector interfaces.MetricCollector, ch chan<- prometheus.Metric) error {
+	var (
+		retries int
+		timeout time.Duration
+	)
+	if cfg, ok := collector.GetConfig().(interfaces.CollectorConfig); ok {
+		retries, timeout = cfg.GetRetryCount(), cfg.GetTimeout()
+	}
+	backoff := collectRetryBackoff
+	for attempt := 0; ; attempt++ {
+		series, err := m.collectOne(collector, ch, timeout)
+		// 超时的收集器仍在后台运行，重试只会再次阻塞
+		if err == nil || series > 0 || attempt >= retries || stderrors.Is(err, errCollectTimeout) {
+			return err
+		}
+		m.logger.Debugf("Collector %s failed without output, retrying in %v (%d/%d): %v",
+			collector.ID(), backoff, attempt+1, retries, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// errCollectTimeout 收集器超过 timeout 仍未返回
+var errCollectTimeout = stderrors.New("collector timed out")
+
+// collectOne 运行单个收集器并记录耗时、输出序列数和错误
+// timeout 大于 0 时收集器超时后被放弃：已输出的序列保留，之后的输出被丢弃，
+// 收集器返回前对它的后续收集直接失败，避免阻塞的 netlink 调用堆积 goroutine
+func (m *ManagerV2) collectOne(collector interfaces.MetricCollector, ch chan<- prometheus.Metric, timeout time.Duration) (series int, err error) {
+	id := collector.ID()
+	m.logger.Debugf("Collecting from collector: %s", id)
+	reporter, hasReporter := collector.(interfaces.ErrorReporter)
+	if _, busy := m.abandoned.Load(id); busy {
+		err = errors.Wrap(errCollectTimeout, errors.ErrCodeMetricsCollect, "previous collection is still running").
+			WithContext("collector", id)
+		m.self.ObserveCollection(id, 0, 0, err)
+		return 0, err
+	}
+	if hasReporter {
+		reporter.SetLastError(nil)
+	}
+
+	// 通过中间通道统计输出的序列数，放弃收集器后不再向 ch 转发
+	var (
+		forwardMu sync.Mutex
+		abandoned bool
+	)
+	counted := make(chan prometheus.Metric)
+	forwarded := make(chan struct{})
+	go func() {
+		defer close(forwarded)
+		for metric := range counted {
+			forwardMu.Lock()
+			if !abandoned {
+				ch <- metric
+				series++
+			}
+			forwardMu.Unlock()
+		}
+	}()
+
+	// runMu 保护 returned 和 gaveUp：放弃标记只在收集器返回之前设置，并由收集器返回时清除，
+	// 收集器恰好在超时时刻返回时不会留下无人清除的标记
+	var (
+		runMu    sync.Mutex
+		returned bool
+		gaveUp   bool
+	)
+	start := time.Now()
+	finished := make(chan error, 1)
+	go func() {
+		var panicErr error
+		defer func() {
+			if rec := recover(); rec != nil {
+				panicErr = errors.New(errors.ErrCodeMetricsCollect, fmt.Sprintf("collector panic: %v", rec)).
+					WithContext("collector", id)
+				m.logger.Errorf("Collector %s panicked: %v", id, rec)
+			}
+			runMu.Lock()
+			returned = true
+			if gaveUp {
+				m.abandoned.Delete(id)
+			}
+			runMu.Unlock()
+			close(counted)
+			finished <- panicErr
+		}()
+		collector.Collect(counted)
+	}()
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case err = <-finished:
+		<-forwarded
+	case <-expired:
+		runMu.Lock()
+		if returned {
+			// 收集器已在超时时刻返回，按正常完成处理
+			runMu.Unlock()
+			err = <-finished
+			<-forwarded
+			break
+		}
+		gaveUp = true
+		m.abandoned.Store(id, true)
+		runMu.Unlock()
+		forwardMu.Lock()
+		abandoned = true
+		forwardMu.Unlock()
+		err = errors.Wrap(errCollectTimeout, errors.ErrCodeMetricsCollect, "collector abandoned").
+			WithContext("collector", id).
+			WithContext("timeout", timeout.String())
+		m.logger.Errorf("Collector %s did not finish within %v, abandoning it", id, timeout)
+	}
+	duration := time.Since(start)
+
+	forwardMu.Lock()
+	n := series
+	forwardMu.Unlock()
+	if err != nil && hasReporter {
+		reporter.SetLastError(err)
+	}
+	if err == nil && hasReporter {
+		err = reporter.GetLastError()
+	}
+	m.self.ObserveCollection(id, duration, n, err)
+	return n, err
+}
+
+// CollectorStates 返回所有已注册收集器的状态，按 ID 排序
+func (m *ManagerV2) CollectorStates() []CollectorState {
+	collectors := m.registry.GetAllCollectors()
+	states := make([]CollectorState, 0, len(collectors))
+	for _, collector := range collectors {
+		states = append(states, collectorState(collector))
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].ID < states[j].ID
+	})
+	return states
+}
+
+// CollectorState 返回单个收集器的状态
+func (m *ManagerV2) CollectorState(id string) (CollectorState, bool) {
+	collector, exists := m.registry.GetCollector(id)
+	if !exists {
+		return CollectorState{}, false
+	}
+	return collectorState(collector), true
+}
+
+// collectorState 生成收集器的状态快照
+func collectorState(collector interfaces.MetricCollector) CollectorState {
+	state := CollectorState{
+		ID:          collector.ID(),
+		Name:        collector.Name(),
+		Description: collector.Description(),
+		Enabled:     collector.Enabled(),
+	}
+	if cfg, ok := collector.GetConfig().(interfaces.CollectorConfig); ok {
+		if timeout := cfg.GetTimeout(); timeout > 0 {
+			state.Timeout = timeout.String()
+		}
+		state.Retries = cfg.GetRetryCount()
+	}
+	if lister, ok := collector.(interface{ GetSupportedMetrics() []string }); ok {
+		state.SupportedMetrics = append([]string(nil), lister.GetSupportedMetrics()...)
+		sort.Strings(state.SupportedMetrics)
+	}
+	if reporter, ok := collector.(interfaces.ErrorReporter); ok {
+		if err := reporter.GetLastError(); err != nil {
+			state.LastError = err.Error()
+		}
+	}
+	if reporter, ok := collector.(interfaces.CollectTimeReporter); ok {
+		state.LastCollect = reporter.GetLastCollectTime()
+	}
+	return state
+}
+
+// CollectorSettings 返回收集器当前生效的配置覆盖项：配置文件中的 collectors 段叠加运行时修改
+func (m *ManagerV2) CollectorSettings(id string) config.CollectorSettings {
+	m.overridesMu.Lock()
+	defer m.overridesMu.Unlock()
+	if settings, ok := m.overrides[id]; ok {
+		return settings
+	}
+	return m.config.Collectors[id]
+}
+
+// UpdateCollector 在运行时应用收集器的启用状态、超时和重试次数，不修改配置文件，热重载后失效
+// 指标级配置在创建收集器时生效，运行时修改返回错误，需要写回配置文件后重载
+func (m *ManagerV2) UpdateCollector(id string, settings config.CollectorSettings) error {
+	collector, exists := m.registry.GetCollector(id)
+	if !exists {
+		return fmt.Errorf("collector %s not found", id)
+	}
+	if !reflect.DeepEqual(settings.Metrics, m.CollectorSettings(id).Metrics) {
+		return fmt.Errorf("collector %s: metric settings can only be changed by persisting them to the config file", id)
+	}
+	resolved, err := config.CollectorsConfig{id: settings}.Resolve(id)
+	if err != nil {
+		return err
+	}
+	if err := collector.SetConfig(resolved); err != nil {
+		return fmt.Errorf("collector %s: %w", id, err)
+	}
+	collector.SetEnabled(resolved.IsEnabled())
+
+	m.overridesMu.Lock()
+	m.overrides[id] = settings
+	m.overridesMu.Unlock()
+	m.logger.Infof("Collector %s updated at runtime: enabled=%v timeout=%v retries=%d",
+		id, resolved.IsEnabled(), resolved.GetTimeout(), resolved.GetRetryCount())
+	return nil
 }
 
 // GetCollector 获取收集器
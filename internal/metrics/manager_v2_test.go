@@ -0,0 +1,317 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gitee.com/openeuler/uos-tc-exporter/internal/metrics/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+)
+
+// stubCollector 测试用收集器，输出一个序列后阻塞到 release 关闭
+type stubCollector struct {
+	cfg     *config.CollectorConfig
+	desc    *prometheus.Desc
+	release chan struct{}
+	calls   int
+}
+
+func (c *stubCollector) ID() string          { return "stub" }
+func (c *stubCollector) Name() string        { return "stub" }
+func (c *stubCollector) Description() string { return "stub" }
+func (c *stubCollector) GetConfig() any      { return c.cfg }
+func (c *stubCollector) SetConfig(any) error { return nil }
+func (c *stubCollector) Enabled() bool       { return true }
+func (c *stubCollector) SetEnabled(bool)     {}
+func (c *stubCollector) Collect(ch chan<- prometheus.Metric) {
+	c.calls++
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, 1)
+	<-c.release
+	// 被放弃后的输出不能再写入已关闭的通道
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, 2)
+}
+
+func TestCollectWithRetryTimeout(t *testing.T) {
+	cfg := config.NewCollectorConfig()
+	cfg.SetTimeout(50 * time.Millisecond)
+	cfg.SetRetryCount(2)
+	collector := &stubCollector{
+		cfg:     cfg,
+		desc:    prometheus.NewDesc("tc_stub", "stub", nil, nil),
+		release: make(chan struct{}),
+	}
+	m := &ManagerV2{self: NewSelfMetrics(), logger: logrus.New()}
+
+	ch := make(chan prometheus.Metric, 10)
+	start := time.Now()
+	err := m.collectWithRetry(collector, ch)
+	if !errors.Is(err, errCollectTimeout) {
+		t.Fatalf("collectWithRetry() error = %v, want timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("collectWithRetry() took %v, want it bounded by the timeout", elapsed)
+	}
+	close(ch)
+	if n := len(ch); n != 1 {
+		t.Errorf("got %d series, want the one sent before the timeout", n)
+	}
+
+	// 被放弃的收集器返回前，后续收集直接失败，不会再次调用 Collect
+	if err := m.collectWithRetry(collector, make(chan prometheus.Metric, 10)); !errors.Is(err, errCollectTimeout) {
+		t.Errorf("second collectWithRetry() error = %v, want timeout", err)
+	}
+	if collector.calls != 1 {
+		t.Errorf("Collect called %d times, want 1 (no retry after a timeout)", collector.calls)
+	}
+
+	close(collector.release)
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, busy := m.abandoned.Load(collector.ID()); !busy {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("abandoned collector was not released after Collect returned")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// sleepCollector 休眠 delay 后返回
+type sleepCollector struct {
+	stubCollector
+	delay time.Duration
+}
+
+func (c *sleepCollector) Collect(ch chan<- prometheus.Metric) {
+	time.Sleep(c.delay)
+}
+
+func TestCollectOneReturnsAtTimeout(t *testing.T) {
+	const timeout = 2 * time.Millisecond
+	collector := &sleepCollector{stubCollector: stubCollector{cfg: config.NewCollectorConfig()}, delay: timeout}
+	m := &ManagerV2{self: NewSelfMetrics(), logger: logrus.New()}
+	m.logger.SetLevel(logrus.PanicLevel)
+
+	// 收集器在超时附近返回，无论判定为完成还是超时，返回后都不能留下放弃标记
+	for i := 0; i < 200; i++ {
+		_, _ = m.collectOne(collector, make(chan prometheus.Metric, 1), timeout)
+		deadline := time.Now().Add(time.Second)
+		for {
+			if _, busy := m.abandoned.Load(collector.ID()); !busy {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("run %d: collector stayed marked as running after Collect returned", i)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+func TestUpdateCollectorDuringCollect(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+	m := NewManagerV2(nil, logger)
+	defer m.Shutdown()
+
+	// 管理 API 的 PATCH 与抓取并发进行，由 -race 检查配置读写
+	stop, done := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			retries := i % 3
+			if err := m.UpdateCollector("qdisc_qdisc", config.CollectorSettings{
+				Timeout: time.Duration(i%20+1) * time.Second,
+				Retries: &retries,
+			}); err != nil {
+				t.Errorf("UpdateCollector() error = %v", err)
+				return
+			}
+		}
+	}()
+	for i := 0; i < 5; i++ {
+		ch := make(chan prometheus.Metric, 1024)
+		go func() {
+			for range ch {
+			}
+		}()
+		m.CollectAll(ch)
+		close(ch)
+		m.CollectorStates()
+	}
+	close(stop)
+	<-done
+
+	retries := 1
+	if err := m.UpdateCollector("qdisc_qdisc", config.CollectorSettings{Timeout: 20 * time.Second, Retries: &retries}); err != nil {
+		t.Fatalf("UpdateCollector() error = %v", err)
+	}
+	if state, _ := m.CollectorState("qdisc_qdisc"); state.Timeout != "20s" || state.Retries != 1 {
+		t.Errorf("collector state after updates = %+v, want timeout 20s and 1 retry", state)
+	}
+}
+
+func TestOptionalQdiscCollectorsDisabledByDefault(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+	m := NewManagerV2(nil, logger)
+	defer m.Shutdown()
+	for _, id := range []string{"qdisc_cbq", "qdisc_choke"} {
+		if _, ok := m.registry.GetCollector(id); ok {
+			t.Errorf("collector %s registered without being enabled in config", id)
+		}
+	}
+	if _, ok := m.registry.GetCollector("qdisc_qdisc"); !ok {
+		t.Error("collector qdisc_qdisc not registered by default")
+	}
+
+	enabled := true
+	m = NewManagerV2(&config.ManagerConfig{
+		Collectors: config.CollectorsConfig{"qdisc_choke": {Enabled: &enabled}},
+	}, logger)
+	defer m.Shutdown()
+	if _, ok := m.registry.GetCollector("qdisc_choke"); !ok {
+		t.Error("collector qdisc_choke not registered after enabling it in config")
+	}
+}
+
+func TestSelectCollectors(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+	m := NewManagerV2(&config.ManagerConfig{}, logger)
+	defer m.Shutdown()
+	if err := m.RegisterCollector(&stubCollector{cfg: config.NewCollectorConfig()}); err != nil {
+		t.Fatalf("RegisterCollector() error = %v", err)
+	}
+	if err := m.DisableCollector("qdisc_codel"); err != nil {
+		t.Fatalf("DisableCollector() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		want    []string
+		wantErr string
+	}{
+		{name: "all enabled", want: []string{"qdisc_qdisc", "stub"}},
+		{name: "include", include: []string{"stub"}, want: []string{"stub"}},
+		{name: "duplicate include", include: []string{"stub", "stub"}, want: []string{"stub"}},
+		{name: "exclude", exclude: []string{"stub"}, want: []string{"qdisc_qdisc"}},
+		{name: "duplicate exclude", exclude: []string{"stub", "stub"}, want: []string{"qdisc_qdisc"}},
+		{name: "include and exclude", include: []string{"stub", "qdisc_qdisc"}, exclude: []string{"stub"}, want: []string{"qdisc_qdisc"}},
+		{name: "include unknown", include: []string{"nope"}, wantErr: "collector nope not found"},
+		{name: "exclude unknown", exclude: []string{"nope"}, wantErr: "collector nope not found"},
+		{name: "include disabled", include: []string{"qdisc_codel"}, wantErr: "collector qdisc_codel is disabled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collectors, err := m.SelectCollectors(tt.include, tt.exclude)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("SelectCollectors() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SelectCollectors() error = %v", err)
+			}
+			got := make([]string, len(collectors))
+			for i, collector := range collectors {
+				got[i] = collector.ID()
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SelectCollectors() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// countingCollector 统计 Collect 调用次数，输出一个序列后阻塞到 release 关闭
+type countingCollector struct {
+	stubCollector
+	started chan struct{}
+	count   atomic.Int32
+}
+
+func (c *countingCollector) Collect(ch chan<- prometheus.Metric) {
+	if c.count.Add(1) == 1 {
+		close(c.started)
+	}
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, 1)
+	<-c.release
+}
+
+func TestConcurrentScrapesShareCollection(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+	m := NewManagerV2(&config.ManagerConfig{MinCollectInterval: time.Minute}, logger)
+	defer m.Shutdown()
+	collector := &countingCollector{
+		stubCollector: stubCollector{
+			cfg:     config.NewCollectorConfig(),
+			desc:    prometheus.NewDesc("tc_stub", "stub", nil, nil),
+			release: make(chan struct{}),
+		},
+		started: make(chan struct{}),
+	}
+	if err := m.RegisterCollector(collector); err != nil {
+		t.Fatalf("RegisterCollector() error = %v", err)
+	}
+
+	// 进行中的收集由并发抓取共享，之后的抓取在 MinCollectInterval 内复用结果
+	const scrapes = 8
+	var wg sync.WaitGroup
+	series := make([]int, scrapes)
+	scrape := func(i int) {
+		defer wg.Done()
+		ch := make(chan prometheus.Metric, 16)
+		if err := m.CollectSelected(ch, []string{"stub"}, nil); err != nil {
+			t.Errorf("CollectSelected() error = %v", err)
+		}
+		series[i] = len(ch)
+	}
+	wg.Add(scrapes)
+	go scrape(0)
+	<-collector.started
+	for i := 1; i < scrapes; i++ {
+		go scrape(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(collector.release)
+	wg.Wait()
+
+	if n := collector.count.Load(); n != 1 {
+		t.Errorf("Collect called %d times for %d concurrent scrapes, want 1", n, scrapes)
+	}
+	for i, n := range series {
+		if n != 1 {
+			t.Errorf("scrape %d got %d series, want 1", i, n)
+		}
+	}
+	scrapesTotal := m.self.scrapes
+	if n := testutil.ToFloat64(scrapesTotal.WithLabelValues(scrapeCollected)); n != 1 {
+		t.Errorf("collected scrapes = %v, want 1", n)
+	}
+	reused := testutil.ToFloat64(scrapesTotal.WithLabelValues(scrapeShared)) +
+		testutil.ToFloat64(scrapesTotal.WithLabelValues(scrapeCached))
+	if reused != scrapes-1 {
+		t.Errorf("shared and cached scrapes = %v, want %d", reused, scrapes-1)
+	}
+}
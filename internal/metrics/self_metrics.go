@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"time"
+
+	"gitee.com/openeuler/uos-tc-exporter/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// SelfMetrics 导出器自身的可观测性指标
+type SelfMetrics struct {
+	collectDuration *prometheus.HistogramVec
+	collections     *prometheus.CounterVec
+	lastErrorCode   *prometheus.GaugeVec
+	series          *prometheus.GaugeVec
+	lastSuccess     *prometheus.GaugeVec
+	netlinkDuration *prometheus.HistogramVec
+	netlinkErrors   *prometheus.CounterVec
+	scrapes         *prometheus.CounterVec
+}
+
+// NewSelfMetrics 创建自身指标
+func NewSelfMetrics() *SelfMetrics {
+	return &SelfMetrics{
+		collectDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "tc_exporter_collector_duration_seconds",
+			Help:    "Duration of a single collector run",
+			Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		}, []string{"collector"}),
+		collections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "tc_exporter_collector_collections_total",
+			Help: "Number of collector runs by result",
+		}, []string{"collector", "result"}),
+		lastErrorCode: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "tc_exporter_collector_last_error_code",
+			Help: "Error code of the last collector run, 0 if it succeeded",
+		}, []string{"collector"}),
+		series: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "tc_exporter_collector_series",
+			Help: "Number of series emitted by the last collector run",
+		}, []string{"collector"}),
+		lastSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "tc_exporter_collector_last_success_timestamp_seconds",
+			Help: "Unix timestamp of the last successful collector run",
+		}, []string{"collector"}),
+		netlinkDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "tc_exporter_netlink_duration_seconds",
+			Help:    "Duration of netlink calls by network namespace and operation",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"namespace", "operation"}),
+		netlinkErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "tc_exporter_netlink_errors_total",
+			Help: "Number of failed netlink calls by network namespace and operation",
+		}, []string{"namespace", "operation"}),
+		scrapes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "tc_exporter_scrapes_total",
+			Help: "Number of collection requests by source: collected runs the collectors, shared joins an in-flight collection, cached reuses a recent one",
+		}, []string{"source"}),
+	}
+}
+
+// ObserveCollection 记录一次收集器运行结果
+func (sm *SelfMetrics) ObserveCollection(collectorID string, duration time.Duration, series int, err error) {
+	sm.collectDuration.WithLabelValues(collectorID).Observe(duration.Seconds())
+	sm.series.WithLabelValues(collectorID).Set(float64(series))
+	if err != nil {
+		sm.collections.WithLabelValues(collectorID, "failure").Inc()
+		sm.lastErrorCode.WithLabelValues(collectorID).Set(float64(errorCode(err)))
+		return
+	}
+	sm.collections.WithLabelValues(collectorID, "success").Inc()
+	sm.lastErrorCode.WithLabelValues(collectorID).Set(0)
+	sm.lastSuccess.WithLabelValues(collectorID).SetToCurrentTime()
+}
+
+// ObserveNetlink 记录一次 netlink 调用耗时，可作为 tc.LatencyObserver 使用
+func (sm *SelfMetrics) ObserveNetlink(namespace, operation string, duration time.Duration, err error) {
+	sm.netlinkDuration.WithLabelValues(namespace, operation).Observe(duration.Seconds())
+	if err != nil {
+		sm.netlinkErrors.WithLabelValues(namespace, operation).Inc()
+	}
+}
+
+// ObserveScrape 记录一次收集请求的结果来源
+func (sm *SelfMetrics) ObserveScrape(source string) {
+	sm.scrapes.WithLabelValues(source).Inc()
+}
+
+// Describe 实现 prometheus.Collector 接口
+func (sm *SelfMetrics) Describe(ch chan<- *prometheus.Desc) {
+	for _, c := range sm.collectors() {
+		c.Describe(ch)
+	}
+}
+
+// Collect 实现 prometheus.Collector 接口
+func (sm *SelfMetrics) Collect(ch chan<- prometheus.Metric) {
+	for _, c := range sm.collectors() {
+		c.Collect(ch)
+	}
+}
+
+func (sm *SelfMetrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		sm.collectDuration, sm.collections, sm.lastErrorCode,
+		sm.series, sm.lastSuccess, sm.netlinkDuration, sm.netlinkErrors, sm.scrapes,
+	}
+}
+
+// errorCode 提取错误码，非自定义错误统一归为指标收集错误
+func errorCode(err error) errors.ErrorCode {
+	if code := errors.GetErrorCode(err); code != 0 {
+		return code
+	}
+	return errors.ErrCodeMetricsCollect
+}
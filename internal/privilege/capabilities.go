@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+// Package privilege 提供 Linux 能力（capabilities）的查询和降权
+package privilege
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// capabilityNames 按编号排列的能力名称，与 linux/capability.h 一致
+var capabilityNames = []string{
+	"CAP_CHOWN",
+	"CAP_DAC_OVERRIDE",
+	"CAP_DAC_READ_SEARCH",
+	"CAP_FOWNER",
+	"CAP_FSETID",
+	"CAP_KILL",
+	"CAP_SETGID",
+	"CAP_SETUID",
+	"CAP_SETPCAP",
+	"CAP_LINUX_IMMUTABLE",
+	"CAP_NET_BIND_SERVICE",
+	"CAP_NET_BROADCAST",
+	"CAP_NET_ADMIN",
+	"CAP_NET_RAW",
+	"CAP_IPC_LOCK",
+	"CAP_IPC_OWNER",
+	"CAP_SYS_MODULE",
+	"CAP_SYS_RAWIO",
+	"CAP_SYS_CHROOT",
+	"CAP_SYS_PTRACE",
+	"CAP_SYS_PACCT",
+	"CAP_SYS_ADMIN",
+	"CAP_SYS_BOOT",
+	"CAP_SYS_NICE",
+	"CAP_SYS_RESOURCE",
+	"CAP_SYS_TIME",
+	"CAP_SYS_TTY_CONFIG",
+	"CAP_MKNOD",
+	"CAP_LEASE",
+	"CAP_AUDIT_WRITE",
+	"CAP_AUDIT_CONTROL",
+	"CAP_SETFCAP",
+	"CAP_MAC_OVERRIDE",
+	"CAP_MAC_ADMIN",
+	"CAP_SYSLOG",
+	"CAP_WAKE_ALARM",
+	"CAP_BLOCK_SUSPEND",
+	"CAP_AUDIT_READ",
+	"CAP_PERFMON",
+	"CAP_BPF",
+	"CAP_CHECKPOINT_RESTORE",
+}
+
+// CapSet 能力集合的位图，第 n 位对应编号为 n 的能力
+type CapSet uint64
+
+// Has 判断集合中是否包含能力 c
+func (s CapSet) Has(c int) bool {
+	return c >= 0 && c < 64 && s&(1<<uint(c)) != 0
+}
+
+// Add 返回加入能力 c 后的集合
+func (s CapSet) Add(c int) CapSet {
+	return s | 1<<uint(c)
+}
+
+// Names 返回集合中能力的名称，按编号排序
+func (s CapSet) Names() []string {
+	var names []string
+	for c := 0; c < 64; c++ {
+		if s.Has(c) {
+			names = append(names, CapabilityName(c))
+		}
+	}
+	return names
+}
+
+// String 返回逗号分隔的能力名称
+func (s CapSet) String() string {
+	return strings.Join(s.Names(), ",")
+}
+
+// CapabilityName 返回能力编号对应的名称，未知编号返回 CAP_<n>
+func CapabilityName(c int) string {
+	if c >= 0 && c < len(capabilityNames) {
+		return capabilityNames[c]
+	}
+	return "CAP_" + strconv.Itoa(c)
+}
+
+// ParseCapability 解析能力名称，不区分大小写，可以省略 CAP_ 前缀
+func ParseCapability(name string) (int, error) {
+	upper := strings.ToUpper(strings.TrimSpace(name))
+	if !strings.HasPrefix(upper, "CAP_") {
+		upper = "CAP_" + upper
+	}
+	for c, capName := range capabilityNames {
+		if capName == upper {
+			return c, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown capability %q", name)
+}
+
+// ParseCapabilities 解析能力名称列表，每项可以是逗号分隔的多个名称
+func ParseCapabilities(names []string) (CapSet, error) {
+	var set CapSet
+	for _, item := range names {
+		for _, name := range strings.Split(item, ",") {
+			if strings.TrimSpace(name) == "" {
+				continue
+			}
+			c, err := ParseCapability(name)
+			if err != nil {
+				return 0, err
+			}
+			set = set.Add(c)
+		}
+	}
+	return set, nil
+}
+
+// lastCap 返回内核支持的最大能力编号
+func lastCap() int {
+	content, err := os.ReadFile("/proc/sys/kernel/cap_last_cap")
+	if err != nil {
+		return unix.CAP_LAST_CAP
+	}
+	c, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil {
+		return unix.CAP_LAST_CAP
+	}
+	return c
+}
+
+// Current 返回调用线程的有效能力和许可能力
+func Current() (effective, permitted CapSet, err error) {
+	hdr := unix.CapUserHeader{Version: unix.LINUX_CAPABILITY_VERSION_3}
+	var data [2]unix.CapUserData
+	if err := unix.Capget(&hdr, &data[0]); err != nil {
+		return 0, 0, fmt.Errorf("capget: %w", err)
+	}
+	effective = CapSet(data[0].Effective) | CapSet(data[1].Effective)<<32
+	permitted = CapSet(data[0].Permitted) | CapSet(data[1].Permitted)<<32
+	return effective, permitted, nil
+}
+
+// setCurrent 将调用线程的有效、许可和可继承能力都设置为 set
+func setCurrent(set CapSet) error {
+	hdr := unix.CapUserHeader{Version: unix.LINUX_CAPABILITY_VERSION_3}
+	low, high := uint32(set), uint32(set>>32)
+	data := [2]unix.CapUserData{
+		{Effective: low, Permitted: low, Inheritable: low},
+		{Effective: high, Permitted: high, Inheritable: high},
+	}
+	if err := unix.Capset(&hdr, &data[0]); err != nil {
+		return fmt.Errorf("capset: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package privilege
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"runtime"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// droppedEnv 降权后重新执行时设置的环境变量，避免重复降权
+const droppedEnv = "UOS_TC_EXPORTER_PRIVILEGES_DROPPED"
+
+// Requirement 导出器需要的一项能力及缺少时的影响
+type Requirement struct {
+	Capability int
+	// Impact 缺少该能力时受影响的功能
+	Impact string
+}
+
+// Requirements 收集 TC 指标需要的能力
+var Requirements = []Requirement{
+	{Capability: unix.CAP_NET_ADMIN, Impact: "netlink TC dumps (qdisc/class/filter) may be refused"},
+	{Capability: unix.CAP_SYS_ADMIN, Impact: "network namespaces other than the default one cannot be entered"},
+}
+
+// Required 返回 Requirements 中全部能力的集合
+func Required() CapSet {
+	var set CapSet
+	for _, req := range Requirements {
+		set = set.Add(req.Capability)
+	}
+	return set
+}
+
+// Options 降权选项
+type Options struct {
+	// User 切换到的用户，用户名或 UID，为空时保持当前用户
+	User string
+	// Group 切换到的组，组名或 GID，为空时使用 User 的主组
+	Group string
+	// Keep 在 Required 之外额外保留的能力，如 CAP_NET_BIND_SERVICE
+	Keep CapSet
+}
+
+// Dropped 判断当前进程是否为降权后重新执行的进程
+func Dropped() bool {
+	return os.Getenv(droppedEnv) != ""
+}
+
+// Drop 只保留 Required 和 opts.Keep 中的能力，可选切换到非特权用户，然后重新执行当前程序
+//
+// 能力是按线程设置的，Go 程序的其他线程不会随 capset 改变，因此在锁定的线程上
+// 缩小边界集、切换用户（PR_SET_KEEPCAPS 保留许可能力）、设置能力并提升为 ambient 能力后
+// 通过 execve 重新执行，新进程的所有线程都只有保留的能力。成功时不返回。
+func Drop(opts Options) error {
+	keep := Required() | opts.Keep
+	uid, gid, err := lookupUser(opts.User, opts.Group)
+	if err != nil {
+		return err
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("resolve executable: %w", err)
+	}
+
+	// 之后的系统调用必须在执行 execve 的同一线程上完成，失败时进程状态不一致，由调用方退出
+	runtime.LockOSThread()
+
+	for c := 0; c <= lastCap(); c++ {
+		if keep.Has(c) {
+			continue
+		}
+		if err := unix.Prctl(unix.PR_CAPBSET_DROP, uintptr(c), 0, 0, 0); err != nil && err != unix.EINVAL {
+			return fmt.Errorf("drop %s from bounding set: %w", CapabilityName(c), err)
+		}
+	}
+
+	if uid >= 0 {
+		if err := unix.Prctl(unix.PR_SET_KEEPCAPS, 1, 0, 0, 0); err != nil {
+			return fmt.Errorf("prctl(PR_SET_KEEPCAPS): %w", err)
+		}
+		if err := syscall.Setgroups([]int{gid}); err != nil {
+			return fmt.Errorf("setgroups(%d): %w", gid, err)
+		}
+		if err := syscall.Setgid(gid); err != nil {
+			return fmt.Errorf("setgid(%d): %w", gid, err)
+		}
+		if err := syscall.Setuid(uid); err != nil {
+			return fmt.Errorf("setuid(%d): %w", uid, err)
+		}
+	}
+
+	if err := setCurrent(keep); err != nil {
+		return err
+	}
+	for c := 0; c < 64; c++ {
+		if !keep.Has(c) {
+			continue
+		}
+		if err := unix.Prctl(unix.PR_CAP_AMBIENT, unix.PR_CAP_AMBIENT_RAISE, uintptr(c), 0, 0); err != nil {
+			return fmt.Errorf("raise ambient %s: %w", CapabilityName(c), err)
+		}
+	}
+
+	env := append(os.Environ(), droppedEnv+"=1")
+	if err := syscall.Exec(exe, os.Args, env); err != nil {
+		return fmt.Errorf("re-exec %s: %w", exe, err)
+	}
+	return nil
+}
+
+// lookupUser 解析用户和组，user 为空时返回 -1
+func lookupUser(name, group string) (uid, gid int, err error) {
+	if name == "" {
+		if group != "" {
+			return -1, -1, fmt.Errorf("a group requires a user to switch to")
+		}
+		return -1, -1, nil
+	}
+	u, err := user.Lookup(name)
+	if err != nil {
+		if u, err = user.LookupId(name); err != nil {
+			return -1, -1, fmt.Errorf("unknown user %q", name)
+		}
+	}
+	if uid, err = strconv.Atoi(u.Uid); err != nil {
+		return -1, -1, fmt.Errorf("invalid uid %q for user %q", u.Uid, name)
+	}
+	gidStr := u.Gid
+	if group != "" {
+		g, err := user.LookupGroup(group)
+		if err != nil {
+			if g, err = user.LookupGroupId(group); err != nil {
+				return -1, -1, fmt.Errorf("unknown group %q", group)
+			}
+		}
+		gidStr = g.Gid
+	}
+	if gid, err = strconv.Atoi(gidStr); err != nil {
+		return -1, -1, fmt.Errorf("invalid gid %q for group %q", gidStr, group)
+	}
+	return uid, gid, nil
+}
+
+// Report 启动自检的结果
+type Report struct {
+	UID       int
+	Effective CapSet
+	// Missing 缺少的必需能力
+	Missing []Requirement
+}
+
+// Check 检查当前进程是否具备 Requirements 中的能力
+func Check() (Report, error) {
+	effective, _, err := Current()
+	if err != nil {
+		return Report{}, err
+	}
+	return check(os.Geteuid(), effective), nil
+}
+
+func check(uid int, effective CapSet) Report {
+	report := Report{UID: uid, Effective: effective}
+	for _, req := range Requirements {
+		if !effective.Has(req.Capability) {
+			report.Missing = append(report.Missing, req)
+		}
+	}
+	return report
+}
@@ -0,0 +1,33 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package privilege
+
+import (
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestParseCapabilities(t *testing.T) {
+	set, err := ParseCapabilities([]string{"CAP_NET_BIND_SERVICE", "chown,cap_sys_admin"})
+	if err != nil {
+		t.Fatalf("ParseCapabilities() error = %v", err)
+	}
+	if got, want := set.String(), "CAP_CHOWN,CAP_NET_BIND_SERVICE,CAP_SYS_ADMIN"; got != want {
+		t.Errorf("ParseCapabilities() = %s, want %s", got, want)
+	}
+	if _, err := ParseCapabilities([]string{"CAP_FLY"}); err == nil {
+		t.Error("ParseCapabilities(CAP_FLY) error = nil, want error")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	report := check(1000, CapSet(0).Add(unix.CAP_NET_ADMIN))
+	if len(report.Missing) != 1 || report.Missing[0].Capability != unix.CAP_SYS_ADMIN {
+		t.Errorf("check() missing = %+v, want only CAP_SYS_ADMIN", report.Missing)
+	}
+	if report := check(0, Required()); len(report.Missing) != 0 {
+		t.Errorf("check(Required()) missing = %+v, want none", report.Missing)
+	}
+}
@@ -0,0 +1,232 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gitee.com/openeuler/uos-tc-exporter/internal/metrics"
+	metricsconfig "gitee.com/openeuler/uos-tc-exporter/internal/metrics/config"
+	"gitee.com/openeuler/uos-tc-exporter/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// maxAdminBodySize 管理 API 请求体的大小上限
+const maxAdminBodySize = 64 << 10
+
+// CollectorPersister 将收集器配置写回配置文件并重载，由 exporter.ConfigManager.PersistCollectorSettings 实现
+type CollectorPersister func(id string, settings metricsconfig.CollectorSettings) error
+
+// collectorPatch PATCH /api/v1/collectors/{id} 的请求体，未设置的字段保持不变
+type collectorPatch struct {
+	Enabled *bool                  `json:"enabled"`
+	Timeout string                 `json:"timeout"`
+	Retries *int                   `json:"retries"`
+	Metrics map[string]metricPatch `json:"metrics"`
+}
+
+// metricPatch 单个指标的修改项
+type metricPatch struct {
+	Enabled *bool             `json:"enabled"`
+	Labels  map[string]string `json:"labels"`
+}
+
+// settings 转换为 collectors 配置段的格式
+func (p collectorPatch) settings() (metricsconfig.CollectorSettings, error) {
+	settings := metricsconfig.CollectorSettings{Enabled: p.Enabled, Retries: p.Retries}
+	if p.Timeout != "" {
+		timeout, err := time.ParseDuration(p.Timeout)
+		if err != nil {
+			return settings, fmt.Errorf("invalid timeout %q: %w", p.Timeout, err)
+		}
+		if timeout <= 0 {
+			return settings, fmt.Errorf("timeout must be positive, got %v", timeout)
+		}
+		settings.Timeout = timeout
+	}
+	if len(p.Metrics) > 0 {
+		settings.Metrics = make(map[string]metricsconfig.MetricSettings, len(p.Metrics))
+		for name, metric := range p.Metrics {
+			settings.Metrics[name] = metricsconfig.MetricSettings{Enabled: metric.Enabled, Labels: metric.Labels}
+		}
+	}
+	return settings, nil
+}
+
+// SetCollectorPersister 设置收集器配置的写回函数，未设置时 persist=true 的请求返回 503
+func (hs *HttpServer) SetCollectorPersister(persist CollectorPersister) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	hs.persistCollector = persist
+}
+
+// registerAdminRoutes 注册收集器管理和 TC 拓扑 API，与指标端点一样受认证和限流保护
+func (hs *HttpServer) registerAdminRoutes(mux *http.ServeMux) {
+	mux.Handle("GET /api/v1/collectors", hs.protected(http.HandlerFunc(hs.listCollectors)))
+	mux.Handle("GET /api/v1/collectors/{id}", hs.protected(http.HandlerFunc(hs.getCollector)))
+	mux.Handle("PATCH /api/v1/collectors/{id}", hs.protected(http.HandlerFunc(hs.patchCollector)))
+	mux.Handle("POST /api/v1/collectors/{id}/enable", hs.protected(hs.setCollectorEnabled(true)))
+	mux.Handle("POST /api/v1/collectors/{id}/disable", hs.protected(hs.setCollectorEnabled(false)))
+	mux.Handle("GET /api/v1/topology", hs.protected(http.HandlerFunc(hs.serveTopology)))
+}
+
+// listCollectors 返回所有已注册收集器的状态
+func (hs *HttpServer) listCollectors(w http.ResponseWriter, r *http.Request) {
+	manager := hs.collectorManager()
+	if manager == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, fmt.Errorf("metrics manager not initialized"))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"collectors": manager.CollectorStates()})
+}
+
+// getCollector 返回单个收集器的状态
+func (hs *HttpServer) getCollector(w http.ResponseWriter, r *http.Request) {
+	manager := hs.collectorManager()
+	if manager == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, fmt.Errorf("metrics manager not initialized"))
+		return
+	}
+	id := r.PathValue("id")
+	state, ok := manager.CollectorState(id)
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, fmt.Errorf("collector %s not found", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, state)
+}
+
+// patchCollector 修改收集器配置，persist=true 时写回配置文件
+func (hs *HttpServer) patchCollector(w http.ResponseWriter, r *http.Request) {
+	var patch collectorPatch
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAdminBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&patch); err != nil {
+		writeAPIError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+	settings, err := patch.settings()
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err)
+		return
+	}
+	hs.updateCollector(w, r, settings)
+}
+
+// setCollectorEnabled 返回启用或禁用收集器的处理器
+func (hs *HttpServer) setCollectorEnabled(enabled bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hs.updateCollector(w, r, metricsconfig.CollectorSettings{Enabled: &enabled})
+	})
+}
+
+// updateCollector 将 patch 叠加到收集器当前配置上，在运行时应用或写回配置文件
+func (hs *HttpServer) updateCollector(w http.ResponseWriter, r *http.Request, patch metricsconfig.CollectorSettings) {
+	id := r.PathValue("id")
+	// 未配置认证时任何人都能修改收集器，只允许只读访问
+	if !hs.auth.Enabled() {
+		writeAPIError(w, http.StatusForbidden, fmt.Errorf("collector changes require authentication to be configured in the web config file"))
+		return
+	}
+	persist := false
+	if value := r.URL.Query().Get("persist"); value != "" {
+		var err error
+		if persist, err = strconv.ParseBool(value); err != nil {
+			writeAPIError(w, http.StatusBadRequest, fmt.Errorf("invalid persist value %q", value))
+			return
+		}
+	}
+
+	manager := hs.collectorManager()
+	if manager == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, fmt.Errorf("metrics manager not initialized"))
+		return
+	}
+	if _, known := metricsconfig.DefaultsFor(id); !known {
+		if _, registered := manager.GetCollector(id); !registered {
+			writeAPIError(w, http.StatusNotFound, fmt.Errorf("collector %s not found", id))
+			return
+		}
+	}
+	settings := manager.CollectorSettings(id).Merge(patch)
+	if _, err := (metricsconfig.CollectorsConfig{id: settings}).Resolve(id); err != nil {
+		writeAPIError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if persist {
+		hs.mu.RLock()
+		persistCollector := hs.persistCollector
+		hs.mu.RUnlock()
+		if persistCollector == nil {
+			writeAPIError(w, http.StatusServiceUnavailable, fmt.Errorf("config persistence is not available"))
+			return
+		}
+		// 写回后配置重载会重建指标管理器，运行时修改随之失效
+		if err := persistCollector(id, settings); err != nil {
+			writeAPIError(w, http.StatusInternalServerError, err)
+			return
+		}
+	} else {
+		if _, ok := manager.GetCollector(id); !ok {
+			writeAPIError(w, http.StatusNotFound,
+				fmt.Errorf("collector %s is not registered, use persist=true to enable it in the config file", id))
+			return
+		}
+		if err := manager.UpdateCollector(id, settings); err != nil {
+			writeAPIError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"collector":   id,
+		"persist":     persist,
+		"remote_addr": r.RemoteAddr,
+	}).Info("Collector updated via admin API")
+	// 写回配置后被禁用的收集器不再注册，只返回 ID
+	state := metrics.CollectorState{ID: id}
+	if manager = hs.collectorManager(); manager != nil {
+		if current, ok := manager.CollectorState(id); ok {
+			state = current
+		}
+	}
+	writeJSON(w, http.StatusOK, state)
+}
+
+// collectorManager 返回当前生效的收集器管理器，热重载后随 MetricsSource 更新
+func (hs *HttpServer) collectorManager() *metrics.ManagerV2 {
+	hs.mu.RLock()
+	source := hs.metrics
+	hs.mu.RUnlock()
+	if source == nil {
+		return nil
+	}
+	return source.GetManager()
+}
+
+// writeJSON 以 JSON 格式输出响应
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logrus.Errorf("Failed to encode JSON response: %v", err)
+	}
+}
+
+// writeAPIError 以 JSON 格式输出错误
+func writeAPIError(w http.ResponseWriter, status int, err error) {
+	if status >= http.StatusInternalServerError {
+		customErr := errors.Wrap(err, errors.ErrCodeServer, "admin API request failed")
+		logrus.WithFields(logrus.Fields{
+			"error_code": customErr.Code,
+			"status":     status,
+		}).Errorf("Admin API request failed: %v", err)
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,185 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"gitee.com/openeuler/uos-tc-exporter/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// dummyBcryptHash 用户不存在时用于比较的哈希，使响应时间与用户存在时一致
+var dummyBcryptHash = []byte("$2y$10$QOauhQNbBCuQDKes6eFzPeMqBSjb7Mr5DUmpZ/VcEd00UAV/LDeSi")
+
+// authState 一份已加载的认证配置，加载成功后只有 verified 缓存会变化
+type authState struct {
+	users  map[string][]byte
+	tokens [][]byte
+	// verified 缓存已校验通过的凭据摘要，避免每次抓取都执行 bcrypt
+	verifiedMu sync.Mutex
+	verified   map[[sha256.Size]byte]struct{}
+}
+
+// Authenticator 基于 Web 配置的认证器，支持 bcrypt 基本认证和静态 Bearer 令牌
+// 配置可在运行时替换，未配置任何认证方式时放行所有请求
+type Authenticator struct {
+	mu    sync.RWMutex
+	state *authState
+}
+
+// NewAuthenticator 创建认证器，初始状态为不启用认证
+func NewAuthenticator() *Authenticator {
+	return &Authenticator{}
+}
+
+// Load 加载认证配置（包括读取令牌文件）但不生效，未配置认证时返回 nil
+func (a *Authenticator) Load(cfg *WebConfig) (*authState, error) {
+	if cfg == nil || !cfg.AuthEnabled() {
+		return nil, nil
+	}
+	state := &authState{
+		users:    make(map[string][]byte, len(cfg.BasicAuthUsers)),
+		verified: make(map[[sha256.Size]byte]struct{}),
+	}
+	for user, hash := range cfg.BasicAuthUsers {
+		state.users[user] = []byte(hash)
+	}
+	if cfg.BearerTokensFile != "" {
+		tokens, err := loadBearerTokens(cfg.BearerTokensFile)
+		if err != nil {
+			return nil, err
+		}
+		state.tokens = tokens
+	}
+	return state, nil
+}
+
+// Set 使加载好的认证配置生效
+func (a *Authenticator) Set(state *authState) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	previous := a.state
+	a.state = state
+	if state == nil {
+		if previous != nil {
+			logrus.Info("HTTP authentication disabled")
+		}
+		return
+	}
+	logrus.Infof("HTTP authentication enabled: %d basic auth users, %d bearer tokens",
+		len(state.users), len(state.tokens))
+}
+
+// Enabled 是否启用认证
+func (a *Authenticator) Enabled() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.state != nil
+}
+
+// Middleware 返回认证中间件，认证失败时返回 401
+func (a *Authenticator) Middleware() HandlerFunc {
+	return func(req *Request) {
+		a.mu.RLock()
+		state := a.state
+		a.mu.RUnlock()
+		if state == nil || state.authorize(req.Request) {
+			return
+		}
+
+		if len(state.users) > 0 {
+			req.ResponseWriter.Header().Set("WWW-Authenticate", `Basic realm="tc-exporter"`)
+		} else {
+			req.ResponseWriter.Header().Set("WWW-Authenticate", `Bearer realm="tc-exporter"`)
+		}
+		customErr := errors.New(errors.ErrCodeAuth, "Unauthorized")
+		customErr.WithContext("remote_addr", req.Request.RemoteAddr).WithContext("path", req.Request.URL.Path)
+		logrus.WithFields(logrus.Fields{
+			"error_code":  customErr.Code,
+			"remote_addr": req.Request.RemoteAddr,
+			"path":        req.Request.URL.Path,
+		}).Debug("HTTP request authentication failed")
+		req.Error = customErr
+		req.Fail(http.StatusUnauthorized)
+	}
+}
+
+// authorize 校验请求携带的基本认证或 Bearer 令牌
+func (s *authState) authorize(r *http.Request) bool {
+	if user, password, ok := r.BasicAuth(); ok {
+		return s.checkBasic(user, password)
+	}
+	auth := r.Header.Get("Authorization")
+	if token, ok := strings.CutPrefix(auth, "Bearer "); ok {
+		return s.checkToken([]byte(strings.TrimSpace(token)))
+	}
+	return false
+}
+
+// checkBasic 校验用户名和密码，校验通过的结果会被缓存
+func (s *authState) checkBasic(user, password string) bool {
+	hash, exists := s.users[user]
+	if !exists {
+		// 仍然执行一次比较，避免通过响应时间探测用户名
+		_ = bcrypt.CompareHashAndPassword(dummyBcryptHash, []byte(password))
+		return false
+	}
+
+	key := sha256.Sum256([]byte(user + ":" + password))
+	s.verifiedMu.Lock()
+	_, cached := s.verified[key]
+	s.verifiedMu.Unlock()
+	if cached {
+		return true
+	}
+
+	if bcrypt.CompareHashAndPassword(hash, []byte(password)) != nil {
+		return false
+	}
+	s.verifiedMu.Lock()
+	s.verified[key] = struct{}{}
+	s.verifiedMu.Unlock()
+	return true
+}
+
+// checkToken 以常数时间比较 Bearer 令牌
+func (s *authState) checkToken(token []byte) bool {
+	matched := 0
+	for _, expected := range s.tokens {
+		matched |= subtle.ConstantTimeCompare(token, expected)
+	}
+	return matched == 1
+}
+
+// loadBearerTokens 读取令牌文件，每行一个令牌
+func loadBearerTokens(path string) ([][]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeConfig, "failed to read bearer tokens file").
+			WithContext("path", path)
+	}
+	var tokens [][]byte
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		tokens = append(tokens, append([]byte(nil), line...))
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New(errors.ErrCodeConfigValidation, "bearer tokens file contains no tokens").
+			WithContext("path", path)
+	}
+	return tokens, nil
+}
@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitee.com/openeuler/uos-tc-exporter/internal/exporter"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHttpServer_protected(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bcrypt.Cost(dummyBcryptHash); err != nil {
+		t.Fatalf("dummy bcrypt hash is invalid: %v", err)
+	}
+	tokensFile := filepath.Join(t.TempDir(), "tokens")
+	if err := os.WriteFile(tokensFile, []byte("# scrape token\ntoken-1\n\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := exporter.DefaultConfig
+	hs := NewHttpServer(cfg, cfg.ScrapePath(), NewMetricsManager(cfg, nil))
+	auth := hs.auth
+	state, err := auth.Load(&WebConfig{
+		BasicAuthUsers:   map[string]string{"prometheus": string(hash)},
+		BearerTokensFile: tokensFile,
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	auth.Set(state)
+
+	handler := hs.protected(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name       string
+		setup      func(r *http.Request)
+		wantStatus int
+	}{
+		{name: "no credentials", setup: func(r *http.Request) {}, wantStatus: http.StatusUnauthorized},
+		{name: "valid basic auth", setup: func(r *http.Request) { r.SetBasicAuth("prometheus", "secret") }, wantStatus: http.StatusOK},
+		{name: "wrong password", setup: func(r *http.Request) { r.SetBasicAuth("prometheus", "wrong") }, wantStatus: http.StatusUnauthorized},
+		{name: "unknown user", setup: func(r *http.Request) { r.SetBasicAuth("admin", "secret") }, wantStatus: http.StatusUnauthorized},
+		{name: "valid bearer token", setup: func(r *http.Request) { r.Header.Set("Authorization", "Bearer token-1") }, wantStatus: http.StatusOK},
+		{name: "invalid bearer token", setup: func(r *http.Request) { r.Header.Set("Authorization", "Bearer token-2") }, wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			tt.setup(req)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+
+	// 关闭认证后放行所有请求
+	auth.Set(nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("status with auth disabled = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
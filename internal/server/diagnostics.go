@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"time"
+
+	"gitee.com/openeuler/uos-tc-exporter/internal/tc"
+	"gitee.com/openeuler/uos-tc-exporter/pkg/errors"
+	"gitee.com/openeuler/uos-tc-exporter/version"
+	"github.com/alecthomas/kingpin"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+var diagnosticDumpDir *string
+
+func init() {
+	diagnosticDumpDir = kingpin.Flag(
+		"diagnostic-dump-dir",
+		"directory to write SIGUSR1 diagnostic dumps to, logged when empty").
+		String()
+}
+
+// reloadFromSignal 响应 SIGHUP 重新加载配置文件
+func (s *Server) reloadFromSignal() {
+	if s.configMgr == nil {
+		return
+	}
+	if err := s.configMgr.Reload(); err != nil {
+		logrus.Errorf("Reload triggered by SIGHUP failed: %v", err)
+		return
+	}
+	logrus.Info("Reload triggered by SIGHUP completed")
+}
+
+// dumpDiagnostics 响应 SIGUSR1 输出诊断信息，用于 HTTP 端口不可达时排查问题
+func (s *Server) dumpDiagnostics() {
+	content := s.buildDiagnostics()
+	if *diagnosticDumpDir == "" {
+		logrus.Infof("Diagnostic dump:\n%s", content)
+		return
+	}
+
+	path := filepath.Join(*diagnosticDumpDir,
+		fmt.Sprintf("%s-dump-%s.txt", s.Name, time.Now().Format("20060102-150405")))
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		customErr := errors.Wrap(err, errors.ErrCodeSystem, "failed to write diagnostic dump")
+		customErr.WithContext("path", path)
+		logrus.WithFields(logrus.Fields{
+			"error_code": customErr.Code,
+			"error":      customErr.Error(),
+			"path":       path,
+		}).Error("Diagnostic dump failed")
+		return
+	}
+	logrus.Infof("Diagnostic dump written to %s", path)
+}
+
+// buildDiagnostics 生成诊断信息：版本、收集器状态、命名空间句柄、当前配置和 goroutine 栈
+func (s *Server) buildDiagnostics() []byte {
+	s.reloadMu.Lock()
+	cfg := s.applied
+	metricsMgr := s.metricsMgr
+	s.reloadMu.Unlock()
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "=== %s diagnostic dump at %s ===\n", s.Name, time.Now().Format(time.RFC3339))
+	fmt.Fprintf(&buf, "%s\n", version.Print(s.Name))
+	fmt.Fprintf(&buf, "goroutines: %d\n", runtime.NumGoroutine())
+
+	if fds, err := tc.CountOpenNamespaceFDs(); err != nil {
+		fmt.Fprintf(&buf, "open netns fds: unknown (%v)\n", err)
+	} else {
+		fmt.Fprintf(&buf, "open netns fds: %d\n", fds)
+	}
+
+	fmt.Fprintf(&buf, "\n=== collectors ===\n")
+	if metricsMgr != nil && metricsMgr.GetManager() != nil {
+		manager := metricsMgr.GetManager()
+		fmt.Fprintf(&buf, "stats: %s\n", manager.GetStats())
+		for _, state := range manager.CollectorStates() {
+			lastCollect := "never"
+			if !state.LastCollect.IsZero() {
+				lastCollect = state.LastCollect.Format(time.RFC3339)
+			}
+			fmt.Fprintf(&buf, "%-16s enabled=%-5v last_collect=%s", state.ID, state.Enabled, lastCollect)
+			if state.LastError != "" {
+				fmt.Fprintf(&buf, " last_error=%q", state.LastError)
+			}
+			buf.WriteString("\n")
+		}
+	}
+
+	fmt.Fprintf(&buf, "\n=== config ===\n")
+	if out, err := yaml.Marshal(cfg); err != nil {
+		fmt.Fprintf(&buf, "failed to marshal config: %v\n", err)
+	} else {
+		buf.Write(out)
+	}
+
+	fmt.Fprintf(&buf, "\n=== goroutines ===\n")
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 2); err != nil {
+		fmt.Fprintf(&buf, "failed to dump goroutines: %v\n", err)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,29 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"gitee.com/openeuler/uos-tc-exporter/internal/exporter"
+)
+
+func TestBuildDiagnosticsRedactsSecrets(t *testing.T) {
+	cfg := exporter.DefaultConfig
+	cfg.Push.BasicAuth = &exporter.BasicAuthConfig{Username: "push", Password: "push-password"}
+	cfg.RemoteWrite.BasicAuth = &exporter.BasicAuthConfig{Username: "rw", Password: "rw-password"}
+	cfg.OTLP.Headers = map[string]exporter.Secret{"Authorization": "Bearer otlp-token"}
+	s := &Server{Name: "tc-exporter", applied: cfg}
+
+	out := string(s.buildDiagnostics())
+	for _, secret := range []string{"push-password", "rw-password", "otlp-token"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("diagnostic dump contains %q", secret)
+		}
+	}
+	if !strings.Contains(out, "username: push") || !strings.Contains(out, "Authorization: <secret>") {
+		t.Errorf("diagnostic dump lost the non-secret config:\n%s", out)
+	}
+}
@@ -0,0 +1,243 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	tc_collector "gitee.com/openeuler/uos-tc-exporter/internal/collectors"
+	"gitee.com/openeuler/uos-tc-exporter/internal/metrics"
+	"gitee.com/openeuler/uos-tc-exporter/internal/tc"
+	"gitee.com/openeuler/uos-tc-exporter/pkg/errors"
+	"gitee.com/openeuler/uos-tc-exporter/pkg/remotewrite"
+	"github.com/alecthomas/kingpin"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/sirupsen/logrus"
+)
+
+// dump 子命令的输出格式
+const (
+	dumpFormatTable = "table"
+	dumpFormatJSON  = "json"
+	dumpFormatProm  = "prom"
+)
+
+var (
+	dumpNetns  *string
+	dumpDevice *string
+	dumpFormat *string
+)
+
+func init() {
+	dump := kingpin.Command(DumpCommand,
+		"Run the collectors once with the default collector settings and print the results, without starting the server or reading the config file")
+	dumpNetns = dump.Flag(
+		"netns",
+		"only collect the named network namespace, default collects all namespaces; "+
+			"scoped collection runs the qdisc and business collectors, the exporter's own app metrics are omitted").
+		String()
+	dumpDevice = dump.Flag(
+		"device",
+		"only print metrics of this device").
+		String()
+	dumpFormat = dump.Flag(
+		"format",
+		"output format: table, json or prom (Prometheus text format)").
+		Default(dumpFormatTable).
+		Enum(dumpFormatTable, dumpFormatJSON, dumpFormatProm)
+}
+
+// dumpSample 展开后的单个样本，直方图和摘要按 Prometheus 文本格式展开为 _bucket、_sum、_count 样本
+type dumpSample struct {
+	Name   string            `json:"name"`
+	Type   string            `json:"type"`
+	Labels map[string]string `json:"labels"`
+	Value  dumpValue         `json:"value"`
+}
+
+// dumpValue JSON 不支持 NaN 和 Inf，这些值按 Prometheus 文本格式输出为字符串
+type dumpValue float64
+
+func (v dumpValue) MarshalJSON() ([]byte, error) {
+	f := float64(v)
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return json.Marshal(formatDumpValue(f))
+	}
+	return json.Marshal(f)
+}
+
+func formatDumpValue(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// RunDump 使用默认收集器配置运行一次全部启用的收集器，按 --format 输出到 out
+// 指定 --netns 时只收集该命名空间，命名空间或设备不存在时返回错误
+func RunDump(out io.Writer) error {
+	// 收集器的初始化日志会混入输出，只保留警告和错误（写入 stderr）
+	logrus.SetLevel(logrus.WarnLevel)
+
+	netns, device := *dumpNetns, *dumpDevice
+	if netns != "" {
+		if err := tc.ValidateNamespaceName(netns); err != nil {
+			return err
+		}
+	}
+
+	manager := metrics.NewManagerV2(nil, logrus.StandardLogger())
+	defer manager.Shutdown()
+	families, err := dumpGather(manager, netns, device)
+	if err != nil {
+		return err
+	}
+	if netns == "" && device != "" {
+		families = filterFamilies(families, "device", device)
+	}
+	return writeDump(out, *dumpFormat, families)
+}
+
+// dumpGather 收集一次并返回指标族，收集器全部失败时返回错误
+func dumpGather(manager *metrics.ManagerV2, netns, device string) ([]*dto.MetricFamily, error) {
+	var collectErr error
+	reg := prometheus.NewRegistry()
+	if err := reg.Register(tc_collector.CollectorFunc(func(ch chan<- prometheus.Metric) {
+		if netns != "" {
+			collectErr = manager.CollectScope(ch, netns, device)
+			return
+		}
+		manager.CollectAll(ch)
+		if health := manager.CollectionHealth(); health.LastError != nil && health.LastSuccess.IsZero() {
+			collectErr = health.LastError
+		}
+	})); err != nil {
+		return nil, err
+	}
+	families, err := reg.Gather()
+	if collectErr != nil {
+		return nil, errors.Wrap(collectErr, errors.ErrCodeMetricsCollect, "dump collection failed").
+			WithContext("netns", netns).WithContext("device", device)
+	}
+	if err != nil {
+		// 部分收集器失败时 Gather 仍返回其余指标
+		logrus.Warnf("Dump gather returned errors: %v", err)
+	}
+	return families, nil
+}
+
+// filterFamilies 只保留标签 name 等于 value 的样本，去掉过滤后为空的指标族
+func filterFamilies(families []*dto.MetricFamily, name, value string) []*dto.MetricFamily {
+	filtered := families[:0]
+	for _, family := range families {
+		var kept []*dto.Metric
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == name && label.GetValue() == value {
+					kept = append(kept, metric)
+					break
+				}
+			}
+		}
+		if len(kept) > 0 {
+			family.Metric = kept
+			filtered = append(filtered, family)
+		}
+	}
+	return filtered
+}
+
+// writeDump 按格式输出指标族
+func writeDump(out io.Writer, format string, families []*dto.MetricFamily) error {
+	switch format {
+	case dumpFormatProm:
+		for _, family := range families {
+			if _, err := expfmt.MetricFamilyToText(out, family); err != nil {
+				return err
+			}
+		}
+		return nil
+	case dumpFormatJSON:
+		samples := dumpSamples(families)
+		if samples == nil {
+			samples = []dumpSample{}
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(samples)
+	case dumpFormatTable:
+		return writeDumpTable(out, dumpSamples(families))
+	}
+	return fmt.Errorf("unknown dump format %q", format)
+}
+
+// writeDumpTable 每个样本一行，按命名空间、设备和指标名排序，namespace 和 device 之外的标签合并为一列
+func writeDumpTable(out io.Writer, samples []dumpSample) error {
+	sort.SliceStable(samples, func(i, j int) bool {
+		a, b := samples[i], samples[j]
+		if a.Labels["namespace"] != b.Labels["namespace"] {
+			return a.Labels["namespace"] < b.Labels["namespace"]
+		}
+		if a.Labels["device"] != b.Labels["device"] {
+			return a.Labels["device"] < b.Labels["device"]
+		}
+		return a.Name < b.Name
+	})
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tDEVICE\tMETRIC\tLABELS\tVALUE")
+	for _, sample := range samples {
+		var labels []string
+		for name, value := range sample.Labels {
+			if name != "namespace" && name != "device" {
+				labels = append(labels, name+"="+value)
+			}
+		}
+		sort.Strings(labels)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", dashIfEmpty(sample.Labels["namespace"]), dashIfEmpty(sample.Labels["device"]),
+			sample.Name, dashIfEmpty(strings.Join(labels, ",")), formatDumpValue(float64(sample.Value)))
+	}
+	return w.Flush()
+}
+
+func dashIfEmpty(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// dumpSamples 将指标族展开为样本，展开规则与 remote_write 相同
+func dumpSamples(families []*dto.MetricFamily) []dumpSample {
+	var samples []dumpSample
+	for _, family := range families {
+		typ := strings.ToLower(family.GetType().String())
+		for _, metric := range family.GetMetric() {
+			remotewrite.ExpandMetric(family, metric, func(name string, value float64, extra []remotewrite.Label) {
+				labels := make(map[string]string, len(metric.GetLabel())+len(extra))
+				for _, label := range metric.GetLabel() {
+					labels[label.GetName()] = label.GetValue()
+				}
+				for _, label := range extra {
+					labels[label.Name] = label.Value
+				}
+				samples = append(samples, dumpSample{Name: name, Type: typ, Labels: labels, Value: dumpValue(value)})
+			})
+		}
+	}
+	return samples
+}
@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/proto"
+)
+
+func dumpTestFamilies() []*dto.MetricFamily {
+	label := func(name, value string) *dto.LabelPair {
+		return &dto.LabelPair{Name: proto.String(name), Value: proto.String(value)}
+	}
+	return []*dto.MetricFamily{
+		{
+			Name: proto.String("qdisc_drops_total"),
+			Help: proto.String("Dropped packets."),
+			Type: dto.MetricType_COUNTER.Enum(),
+			Metric: []*dto.Metric{
+				{
+					Label:   []*dto.LabelPair{label("device", "eth0"), label("kind", "fq_codel"), label("namespace", "default")},
+					Counter: &dto.Counter{Value: proto.Float64(7)},
+				},
+				{
+					Label:   []*dto.LabelPair{label("device", "eth1"), label("kind", "htb"), label("namespace", "blue")},
+					Counter: &dto.Counter{Value: proto.Float64(2)},
+				},
+			},
+		},
+		{
+			Name: proto.String("qdisc_delay_seconds"),
+			Type: dto.MetricType_HISTOGRAM.Enum(),
+			Metric: []*dto.Metric{{
+				Label: []*dto.LabelPair{label("device", "eth0"), label("namespace", "default")},
+				Histogram: &dto.Histogram{
+					SampleCount: proto.Uint64(3),
+					SampleSum:   proto.Float64(0.5),
+					Bucket:      []*dto.Bucket{{UpperBound: proto.Float64(0.1), CumulativeCount: proto.Uint64(2)}},
+				},
+			}},
+		},
+		{
+			Name:   proto.String("qdisc_ratio"),
+			Type:   dto.MetricType_GAUGE.Enum(),
+			Metric: []*dto.Metric{{Gauge: &dto.Gauge{Value: proto.Float64(math.NaN())}}},
+		},
+	}
+}
+
+func TestWriteDump(t *testing.T) {
+	var table bytes.Buffer
+	if err := writeDump(&table, dumpFormatTable, dumpTestFamilies()); err != nil {
+		t.Fatalf("writeDump(table) error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(table.String()), "\n")
+	// 表头加 2 个计数器、直方图展开的 4 个样本和 1 个仪表
+	if len(lines) != 8 {
+		t.Fatalf("table has %d lines, want 8:\n%s", len(lines), table.String())
+	}
+	if fields := strings.Fields(lines[1]); strings.Join(fields, " ") != "- - qdisc_ratio - NaN" {
+		t.Errorf("first row = %q, want the unlabeled gauge sorted first", lines[1])
+	}
+	if fields := strings.Fields(lines[2]); strings.Join(fields, " ") != "blue eth1 qdisc_drops_total kind=htb 2" {
+		t.Errorf("second row = %q", lines[2])
+	}
+
+	var out bytes.Buffer
+	if err := writeDump(&out, dumpFormatJSON, dumpTestFamilies()); err != nil {
+		t.Fatalf("writeDump(json) error = %v", err)
+	}
+	var samples []struct {
+		Name   string            `json:"name"`
+		Labels map[string]string `json:"labels"`
+		Value  any               `json:"value"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &samples); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out.String())
+	}
+	if len(samples) != 7 || samples[0].Value != 7.0 || samples[6].Value != "NaN" {
+		t.Errorf("json samples = %+v", samples)
+	}
+	if s := samples[3]; s.Name != "qdisc_delay_seconds_bucket" || s.Labels["le"] != "+Inf" || s.Value != 3.0 {
+		t.Errorf("+Inf bucket sample = %+v", s)
+	}
+
+	out.Reset()
+	if err := writeDump(&out, dumpFormatProm, filterFamilies(dumpTestFamilies(), "device", "eth1")); err != nil {
+		t.Fatalf("writeDump(prom) error = %v", err)
+	}
+	want := "# HELP qdisc_drops_total Dropped packets.\n# TYPE qdisc_drops_total counter\n" +
+		"qdisc_drops_total{device=\"eth1\",kind=\"htb\",namespace=\"blue\"} 2\n"
+	if out.String() != want {
+		t.Errorf("prom output = %q, want %q", out.String(), want)
+	}
+}
@@ -5,12 +5,21 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
+	"gitee.com/openeuler/uos-tc-exporter/internal/exporter"
+	"gitee.com/openeuler/uos-tc-exporter/internal/metrics"
+	"gitee.com/openeuler/uos-tc-exporter/internal/tc"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
 )
 
 // HealthStatus 健康状态结构
@@ -23,18 +32,109 @@ type HealthStatus struct {
 }
 
 // HealthChecker 健康检查器接口
+// Check 返回 DegradedError 表示部分失败，服务仍可用
 type HealthChecker interface {
 	Check() error
 	Name() string
 }
 
+// 单个检查器的状态
+const (
+	checkStatusOK       = "ok"
+	checkStatusDegraded = "degraded"
+	checkStatusFailed   = "failed"
+)
+
+// 整体健康状态：关键检查器失败时为 unhealthy，非关键检查器失败或任一检查器部分失败时为 degraded
+const (
+	healthStatusHealthy   = "healthy"
+	healthStatusDegraded  = "degraded"
+	healthStatusUnhealthy = "unhealthy"
+)
+
+// healthHistorySize 每个检查器保留的最近检查结果数
+const healthHistorySize = 20
+
+var (
+	healthStatusDesc = prometheus.NewDesc(
+		"tc_exporter_health_status",
+		"Overall health state of the exporter, 1 for the current state.",
+		[]string{"status"}, nil,
+	)
+	healthCheckStatusDesc = prometheus.NewDesc(
+		"tc_exporter_health_check_status",
+		"State of each health checker, 1 for the current state.",
+		[]string{"checker", "critical", "status"}, nil,
+	)
+)
+
+// DegradedError 检查器部分失败，例如 40 个命名空间中只有 1 个无法访问
+type DegradedError struct {
+	Err error
+}
+
+func (e *DegradedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *DegradedError) Unwrap() error {
+	return e.Err
+}
+
+// Degraded 将检查错误标记为部分失败，err 为 nil 时返回 nil
+func Degraded(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &DegradedError{Err: err}
+}
+
+// checkStatus 将检查结果转换为检查器状态
+func checkStatus(err error) string {
+	var degraded *DegradedError
+	switch {
+	case err == nil:
+		return checkStatusOK
+	case errors.As(err, &degraded):
+		return checkStatusDegraded
+	default:
+		return checkStatusFailed
+	}
+}
+
+// CheckResult 单次健康检查的结果
+type CheckResult struct {
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Duration  string    `json:"duration"`
+}
+
+// CheckerDetail /health/<checker> 的响应，History 按时间倒序
+type CheckerDetail struct {
+	Name     string        `json:"name"`
+	Critical bool          `json:"critical"`
+	Status   string        `json:"status"`
+	Error    string        `json:"error,omitempty"`
+	Checked  time.Time     `json:"checked_at"`
+	History  []CheckResult `json:"history"`
+}
+
 // HealthManager 健康状态管理器
 type HealthManager struct {
-	startTime  time.Time
-	version    string
-	checkers   []HealthChecker
-	isReady    atomic.Bool
-	logger     *logrus.Logger
+	startTime time.Time
+	version   string
+	checkers  []*cachedChecker
+	isReady   atomic.Bool
+	logger    *logrus.Logger
+	// configMu 保护 config
+	configMu sync.RWMutex
+	config   exporter.HealthConfig
+	// readyCheck 判断是否已完成首次成功收集，collected 在其返回 true 后锁存
+	readyCheck func() bool
+	collected  atomic.Bool
+	// registry 只包含健康状态指标，不随热重载重建
+	registry *prometheus.Registry
 }
 
 // NewHealthManager 创建健康状态管理器
@@ -43,17 +143,41 @@ func NewHealthManager(version string, logger *logrus.Logger) *HealthManager {
 		logger = logrus.New()
 	}
 
-	return &HealthManager{
+	h := &HealthManager{
 		startTime: time.Now(),
 		version:   version,
 		logger:    logger,
 		isReady:   atomic.Bool{},
+		config:    exporter.DefaultConfig.Health,
+		registry:  prometheus.NewRegistry(),
 	}
+	h.registry.MustRegister(h)
+	return h
 }
 
-// RegisterChecker 注册健康检查器
-func (h *HealthManager) RegisterChecker(checker HealthChecker) {
-	h.checkers = append(h.checkers, checker)
+// SetConfig 更新检查超时和缓存时间，热重载时调用
+func (h *HealthManager) SetConfig(cfg exporter.HealthConfig) {
+	h.configMu.Lock()
+	defer h.configMu.Unlock()
+	h.config = cfg
+}
+
+// Config 返回当前的健康检查配置
+func (h *HealthManager) Config() exporter.HealthConfig {
+	h.configMu.RLock()
+	defer h.configMu.RUnlock()
+	return h.config
+}
+
+// RegisterChecker 注册健康检查器，critical 为 false 时检查失败只使整体状态降为 degraded
+// 检查器带有超时并缓存结果，避免探测请求反复触发开销较大的检查
+func (h *HealthManager) RegisterChecker(checker HealthChecker, critical bool) {
+	h.checkers = append(h.checkers, &cachedChecker{checker: checker, config: h.Config, critical: critical})
+}
+
+// SetReadyCheck 设置首次成功收集的判断函数，设置后 /ready 在其返回 true 之前保持未就绪
+func (h *HealthManager) SetReadyCheck(check func() bool) {
+	h.readyCheck = check
 }
 
 // SetReady 设置服务就绪状态
@@ -62,9 +186,20 @@ func (h *HealthManager) SetReady(ready bool) {
 	h.logger.Infof("Service readiness set to: %v", ready)
 }
 
-// IsReady 检查服务是否就绪
+// IsReady 检查服务是否就绪：已设置为就绪，且已完成首次成功收集
 func (h *HealthManager) IsReady() bool {
-	return h.isReady.Load()
+	if !h.isReady.Load() {
+		return false
+	}
+	if h.readyCheck == nil || h.collected.Load() {
+		return true
+	}
+	if h.readyCheck() {
+		h.collected.Store(true)
+		h.logger.Info("First successful collection finished, service is ready")
+		return true
+	}
+	return false
 }
 
 // GetUptime 获取运行时间
@@ -72,66 +207,135 @@ func (h *HealthManager) GetUptime() string {
 	return time.Since(h.startTime).String()
 }
 
-// HealthHandler 健康检查处理器
-func (h *HealthManager) HealthHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-
-	status := "healthy"
-	details := make(map[string]interface{})
-	var errors []string
+// Gatherer 返回健康状态指标的 Gatherer，抓取时按缓存规则执行检查
+func (h *HealthManager) Gatherer() prometheus.Gatherer {
+	return h.registry
+}
 
-	// 执行所有健康检查
-	for _, checker := range h.checkers {
-		if err := checker.Check(); err != nil {
-			status = "unhealthy"
-			errors = append(errors, fmt.Sprintf("%s: %v", checker.Name(), err))
-			details[checker.Name()] = map[string]interface{}{
-				"status":  "failed",
-				"error":   err.Error(),
-				"message": "Health check failed",
-			}
-		} else {
-			details[checker.Name()] = map[string]interface{}{
-				"status":  "ok",
-				"message": "Health check passed",
+// evaluate 并发执行所有检查器，返回整体状态和与 checkers 顺序一致的检查结果
+func (h *HealthManager) evaluate() (string, []error) {
+	results := make([]error, len(h.checkers))
+	var wg sync.WaitGroup
+	for i, checker := range h.checkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = checker.Check()
+		}()
+	}
+	wg.Wait()
+
+	status := healthStatusHealthy
+	for i, checker := range h.checkers {
+		switch checkStatus(results[i]) {
+		case checkStatusFailed:
+			if checker.critical {
+				return healthStatusUnhealthy, results
 			}
+			status = healthStatusDegraded
+		case checkStatusDegraded:
+			status = healthStatusDegraded
 		}
 	}
+	return status, results
+}
 
-	// 如果有错误，设置HTTP状态码
-	if status == "unhealthy" {
-		w.WriteHeader(http.StatusServiceUnavailable)
-		// 在错误响应中包含错误详情
-		response := HealthStatus{
-			Status:    status,
-			Timestamp: time.Now().UTC().Format(time.RFC3339),
-			Version:   h.version,
-			Uptime:    h.GetUptime(),
-			Details:   details,
+// HealthHandler 健康检查处理器，只有关键检查器失败时返回 503
+func (h *HealthManager) HealthHandler(w http.ResponseWriter, r *http.Request) {
+	status, results := h.evaluate()
+	details := make(map[string]interface{}, len(h.checkers))
+	for i, checker := range h.checkers {
+		detail := map[string]interface{}{
+			"status":   checkStatus(results[i]),
+			"critical": checker.critical,
+			"message":  "Health check passed",
 		}
-		
-		if err := json.NewEncoder(w).Encode(response); err != nil {
-			h.logger.Errorf("Failed to encode health response: %v", err)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		if err := results[i]; err != nil {
+			detail["error"] = err.Error()
+			detail["message"] = "Health check failed"
 		}
-		return
+		details[checker.Name()] = detail
 	}
 
-	// 健康状态响应
-	response := HealthStatus{
+	code := http.StatusOK
+	if status == healthStatusUnhealthy {
+		code = http.StatusServiceUnavailable
+	}
+	h.writeJSON(w, code, HealthStatus{
 		Status:    status,
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
 		Version:   h.version,
 		Uptime:    h.GetUptime(),
 		Details:   details,
+	})
+}
+
+// CheckerHandler 单个检查器的详情处理器，返回当前结果和最近的检查历史，检查失败时返回 503
+func (h *HealthManager) CheckerHandler(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("checker")
+	for _, checker := range h.checkers {
+		if checker.Name() != name {
+			continue
+		}
+		err := checker.Check()
+		detail := CheckerDetail{
+			Name:     name,
+			Critical: checker.critical,
+			Status:   checkStatus(err),
+			Checked:  checker.checkedTime(),
+			History:  checker.History(),
+		}
+		if err != nil {
+			detail.Error = err.Error()
+		}
+		code := http.StatusOK
+		if detail.Status == checkStatusFailed {
+			code = http.StatusServiceUnavailable
+		}
+		h.writeJSON(w, code, detail)
+		return
 	}
+	h.writeJSON(w, http.StatusNotFound, map[string]string{"error": fmt.Sprintf("health checker %s not found", name)})
+}
 
-	if err := json.NewEncoder(w).Encode(response); err != nil {
+// writeJSON 以 JSON 格式输出健康检查响应
+func (h *HealthManager) writeJSON(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
 		h.logger.Errorf("Failed to encode health response: %v", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
 
+// Describe 实现 prometheus.Collector
+func (h *HealthManager) Describe(ch chan<- *prometheus.Desc) {
+	ch <- healthStatusDesc
+	ch <- healthCheckStatusDesc
+}
+
+// Collect 实现 prometheus.Collector，以状态集的形式输出整体和每个检查器的状态
+func (h *HealthManager) Collect(ch chan<- prometheus.Metric) {
+	status, results := h.evaluate()
+	for _, s := range []string{healthStatusHealthy, healthStatusDegraded, healthStatusUnhealthy} {
+		ch <- prometheus.MustNewConstMetric(healthStatusDesc, prometheus.GaugeValue, boolToFloat(s == status), s)
+	}
+	for i, checker := range h.checkers {
+		current := checkStatus(results[i])
+		critical := strconv.FormatBool(checker.critical)
+		for _, s := range []string{checkStatusOK, checkStatusDegraded, checkStatusFailed} {
+			ch <- prometheus.MustNewConstMetric(healthCheckStatusDesc, prometheus.GaugeValue,
+				boolToFloat(s == current), checker.Name(), critical, s)
+		}
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
 // ReadyHandler 就绪检查处理器
 func (h *HealthManager) ReadyHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -196,6 +400,103 @@ func (b *BasicHealthChecker) Name() string {
 	return b.name
 }
 
+// cachedChecker 为检查器加上超时、结果缓存和检查历史
+// 并发的检查共享同一次执行；超时后结果记为失败并缓存，后台仍在运行的检查结束后更新缓存
+type cachedChecker struct {
+	checker  HealthChecker
+	config   func() exporter.HealthConfig
+	critical bool
+	group    singleflight.Group
+	// mu 保护 result、checkedAt 和 history
+	mu        sync.Mutex
+	result    error
+	checkedAt time.Time
+	// history 最近 healthHistorySize 次检查结果的环形缓冲区，next 为下一个写入位置
+	history []CheckResult
+	next    int
+}
+
+// Check 返回缓存内的结果，或执行一次带超时的检查
+func (c *cachedChecker) Check() error {
+	cfg := c.config()
+	c.mu.Lock()
+	if !c.checkedAt.IsZero() && time.Since(c.checkedAt) < cfg.CacheTTL {
+		result := c.result
+		c.mu.Unlock()
+		return result
+	}
+	c.mu.Unlock()
+
+	start := time.Now()
+	done := c.group.DoChan(c.checker.Name(), func() (any, error) {
+		err := c.run()
+		c.store(err, start)
+		return nil, err
+	})
+	timer := time.NewTimer(cfg.CheckTimeout)
+	defer timer.Stop()
+	select {
+	case res := <-done:
+		return res.Err
+	case <-timer.C:
+		err := fmt.Errorf("health check timed out after %v", cfg.CheckTimeout)
+		c.store(err, start)
+		return err
+	}
+}
+
+// run 执行检查并将 panic 转换为错误
+func (c *cachedChecker) run() (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("health check panicked: %v", rec)
+		}
+	}()
+	return c.checker.Check()
+}
+
+// store 缓存检查结果并写入检查历史
+func (c *cachedChecker) store(err error, start time.Time) {
+	now := time.Now()
+	result := CheckResult{Status: checkStatus(err), Timestamp: now, Duration: now.Sub(start).String()}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.result, c.checkedAt = err, now
+	if len(c.history) < healthHistorySize {
+		c.history = append(c.history, result)
+	} else {
+		c.history[c.next] = result
+	}
+	c.next = (c.next + 1) % healthHistorySize
+}
+
+// History 返回最近的检查结果，最新的在前
+func (c *cachedChecker) History() []CheckResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	history := make([]CheckResult, 0, len(c.history))
+	for i := 1; i <= len(c.history); i++ {
+		history = append(history, c.history[(c.next-i+len(c.history))%len(c.history)])
+	}
+	return history
+}
+
+// checkedTime 返回最近一次检查结束的时间
+func (c *cachedChecker) checkedTime() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.checkedAt
+}
+
+// Name 获取检查器名称
+func (c *cachedChecker) Name() string {
+	return c.checker.Name()
+}
+
 // TCHealthChecker TC 相关健康检查
 type TCHealthChecker struct {
 	logger *logrus.Logger
@@ -209,11 +510,13 @@ func NewTCHealthChecker(logger *logrus.Logger) *TCHealthChecker {
 	return &TCHealthChecker{logger: logger}
 }
 
-// Check 检查TC功能是否正常
+// Check 连接 rtnetlink 并在默认命名空间中导出一次链路列表，确认 netlink 可用
 func (t *TCHealthChecker) Check() error {
-	// 这里可以添加TC相关的健康检查逻辑
-	// 例如检查netlink连接、TC命令可用性等
-	t.logger.Debug("TC health check passed")
+	links, err := tc.GetInterfacesInNamespace(tc.DefaultNetNS)
+	if err != nil {
+		return fmt.Errorf("rtnetlink link dump failed: %w", err)
+	}
+	t.logger.Debugf("TC health check passed, %d links in default namespace", len(links))
 	return nil
 }
 
@@ -222,22 +525,89 @@ func (t *TCHealthChecker) Name() string {
 	return "tc"
 }
 
+// NamespaceHealthChecker 逐个网络命名空间导出链路列表，部分命名空间失败时返回 DegradedError
+type NamespaceHealthChecker struct {
+	logger *logrus.Logger
+}
+
+// NewNamespaceHealthChecker 创建命名空间健康检查器
+func NewNamespaceHealthChecker(logger *logrus.Logger) *NamespaceHealthChecker {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	return &NamespaceHealthChecker{logger: logger}
+}
+
+// Check 所有命名空间都失败时返回错误，部分失败时返回 DegradedError
+func (n *NamespaceHealthChecker) Check() error {
+	names, err := tc.GetNetworkNamespaceNames()
+	if err != nil {
+		return fmt.Errorf("list network namespaces failed: %w", err)
+	}
+	var failed []string
+	for _, name := range names {
+		if _, err := tc.GetInterfacesInNamespace(name); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+	switch {
+	case len(failed) == 0:
+		n.logger.Debugf("Namespace health check passed, %d namespaces", len(names))
+		return nil
+	case len(failed) == len(names):
+		return fmt.Errorf("all %d namespaces failed: %s", len(names), strings.Join(failed, "; "))
+	default:
+		return Degraded(fmt.Errorf("%d of %d namespaces failed: %s", len(failed), len(names), strings.Join(failed, "; ")))
+	}
+}
+
+// Name 获取检查器名称
+func (n *NamespaceHealthChecker) Name() string {
+	return "namespaces"
+}
+
 // MetricsHealthChecker 指标收集健康检查
 type MetricsHealthChecker struct {
 	logger *logrus.Logger
+	// manager 返回当前生效的收集器管理器，热重载后随之更新
+	manager func() *metrics.ManagerV2
+	config  func() exporter.HealthConfig
 }
 
 // NewMetricsHealthChecker 创建指标健康检查器
-func NewMetricsHealthChecker(logger *logrus.Logger) *MetricsHealthChecker {
+func NewMetricsHealthChecker(logger *logrus.Logger, manager func() *metrics.ManagerV2, config func() exporter.HealthConfig) *MetricsHealthChecker {
 	if logger == nil {
 		logger = logrus.New()
 	}
-	return &MetricsHealthChecker{logger: logger}
+	return &MetricsHealthChecker{logger: logger, manager: manager, config: config}
 }
 
-// Check 检查指标收集功能
+// Check 最近连续 MaxConsecutiveFailures 次收集失败、持续失败超过 StaleAfter
+// 或单次收集运行超过 StaleAfter 时返回错误，失败次数未达到上限时返回 DegradedError；
+// 尚未收集过时不视为失败，由 /ready 反映
 func (m *MetricsHealthChecker) Check() error {
-	// 这里可以添加指标收集相关的健康检查逻辑
+	manager := m.manager()
+	if manager == nil {
+		return fmt.Errorf("metrics manager not initialized")
+	}
+	cfg := m.config()
+	health := manager.CollectionHealth()
+	if !health.RunningSince.IsZero() {
+		if running := time.Since(health.RunningSince); running > cfg.StaleAfter {
+			return fmt.Errorf("collection has been running for %v", running.Round(time.Second))
+		}
+	}
+	if health.ConsecutiveFailures >= cfg.MaxConsecutiveFailures {
+		return fmt.Errorf("last %d collections failed: %v", health.ConsecutiveFailures, health.LastError)
+	}
+	if health.ConsecutiveFailures > 0 && !health.LastSuccess.IsZero() {
+		if stale := time.Since(health.LastSuccess); stale > cfg.StaleAfter {
+			return fmt.Errorf("no successful collection for %v: %v", stale.Round(time.Second), health.LastError)
+		}
+	}
+	if health.ConsecutiveFailures > 0 {
+		return Degraded(fmt.Errorf("last %d collections failed: %v", health.ConsecutiveFailures, health.LastError))
+	}
 	m.logger.Debug("Metrics health check passed")
 	return nil
 }
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gitee.com/openeuler/uos-tc-exporter/internal/exporter"
+)
+
+func TestCachedChecker(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	checker := &cachedChecker{
+		checker: NewBasicHealthChecker("slow", func() error {
+			if calls.Add(1) == 1 {
+				<-release
+			}
+			return errors.New("boom")
+		}),
+		config: func() exporter.HealthConfig {
+			return exporter.HealthConfig{CheckTimeout: 20 * time.Millisecond, CacheTTL: time.Hour}
+		},
+	}
+
+	// 第一次检查超时，超时结果被缓存，不会再次运行检查
+	if err := checker.Check(); err == nil || err.Error() != "health check timed out after 20ms" {
+		t.Fatalf("first Check() error = %v, want timeout", err)
+	}
+	if err := checker.Check(); err == nil || calls.Load() != 1 {
+		t.Fatalf("second Check() error = %v calls = %d, want cached timeout and 1 call", err, calls.Load())
+	}
+
+	// 后台检查结束后更新缓存
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for {
+		if err := checker.Check(); err != nil && err.Error() == "boom" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("cached result not updated after the slow check finished")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, want 1", calls.Load())
+	}
+}
+
+func TestHealthManagerStatus(t *testing.T) {
+	ok := func() error { return nil }
+	failed := func() error { return errors.New("down") }
+	degraded := func() error { return Degraded(errors.New("1 of 40 namespaces failed")) }
+
+	tests := []struct {
+		name     string
+		critical func() error
+		optional func() error
+		want     string
+	}{
+		{"all ok", ok, ok, healthStatusHealthy},
+		{"non-critical failed", ok, failed, healthStatusDegraded},
+		{"critical degraded", degraded, ok, healthStatusDegraded},
+		{"critical failed", failed, degraded, healthStatusUnhealthy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHealthManager("test", nil)
+			h.RegisterChecker(NewBasicHealthChecker("critical", tt.critical), true)
+			h.RegisterChecker(NewBasicHealthChecker("optional", tt.optional), false)
+			if got, _ := h.evaluate(); got != tt.want {
+				t.Errorf("evaluate() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCachedCheckerHistory(t *testing.T) {
+	var calls atomic.Int32
+	checker := &cachedChecker{
+		checker: NewBasicHealthChecker("flaky", func() error {
+			if calls.Add(1)%2 == 0 {
+				return errors.New("boom")
+			}
+			return nil
+		}),
+		config: func() exporter.HealthConfig {
+			return exporter.HealthConfig{CheckTimeout: time.Second}
+		},
+	}
+	for i := 0; i < healthHistorySize+3; i++ {
+		checker.Check()
+	}
+
+	history := checker.History()
+	if len(history) != healthHistorySize {
+		t.Fatalf("len(History()) = %d, want %d", len(history), healthHistorySize)
+	}
+	// 最新的结果在前：第 23 次成功，第 22 次失败
+	if history[0].Status != checkStatusOK || history[1].Status != checkStatusFailed || history[1].Error != "boom" {
+		t.Errorf("History()[:2] = %+v, want newest ok then failed", history[:2])
+	}
+	for i := 1; i < len(history); i++ {
+		if history[i].Timestamp.After(history[i-1].Timestamp) {
+			t.Fatalf("History() not in reverse chronological order at %d", i)
+		}
+	}
+}
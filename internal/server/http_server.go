@@ -6,49 +6,87 @@ package server
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
+	"reflect"
+	"strings"
 	"sync"
 	"time"
 
 	"gitee.com/openeuler/uos-tc-exporter/internal/exporter"
+	"gitee.com/openeuler/uos-tc-exporter/internal/metrics"
+	"gitee.com/openeuler/uos-tc-exporter/internal/tc"
 	"gitee.com/openeuler/uos-tc-exporter/pkg/errors"
 	"gitee.com/openeuler/uos-tc-exporter/pkg/ratelimit"
+	"gitee.com/openeuler/uos-tc-exporter/version"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
 )
 
+// MetricsSource 指标端点的数据来源，由 MetricsManager 实现
+type MetricsSource interface {
+	// GetGatherer 返回完整抓取使用的 Gatherer
+	GetGatherer() prometheus.Gatherer
+	// FilteredGatherer 返回只包含所选收集器的 Gatherer
+	FilteredGatherer(include, exclude []string) (prometheus.Gatherer, error)
+	// ProbeGatherer 返回只收集指定命名空间（及可选设备）的 Gatherer
+	ProbeGatherer(netns, device string) (prometheus.Gatherer, error)
+	// GetManager 返回收集器管理器，供管理 API 使用
+	GetManager() *metrics.ManagerV2
+}
+
 // HttpServer 负责HTTP服务器管理
 type HttpServer struct {
-	server        *http.Server
-	handlers      []HandlerFunc
-	handlersMu    sync.RWMutex // 保护handlers切片的读写锁
-	config        exporter.Config
-	metricsPath   string
-	promReg       *prometheus.Registry
-	healthManager *HealthManager
-	version       string
+	// servers 每个监听地址一个 http.Server，按配置顺序排列，Run 之前为空
+	servers     []*listenerServer
+	handlers    []HandlerFunc
+	handlersMu  sync.RWMutex // 保护handlers切片的读写锁
+	mu          sync.RWMutex // 保护热重载时会替换的 config/metricsPath/metrics/metricsHandler/limiter/mux/servers
+	config      exporter.Config
+	metricsPath string
+	metrics     MetricsSource
+	// metricsHandler 完整抓取的指标编码处理器，随 MetricsSource 一起创建，不在每次请求时重建
+	metricsHandler http.Handler
+	limiter        *requestLimiter
+	mux            *http.ServeMux
+	healthManager  *HealthManager
+	tls            *TLSManager
+	auth           *Authenticator
+	// persistCollector 管理 API 写回收集器配置的函数
+	persistCollector CollectorPersister
+	version          string
+	running          bool
+	serveErr         chan error
+	stopped          chan struct{}
+	stopOnce         sync.Once
 }
 
 // NewHttpServer 创建新的HTTP服务器
-func NewHttpServer(config exporter.Config, metricsPath string, promReg *prometheus.Registry) *HttpServer {
-	return &HttpServer{
-		config:      config,
-		metricsPath: metricsPath,
-		promReg:     promReg,
-		version:     "1.0.0", // 设置默认版本号
+func NewHttpServer(config exporter.Config, metricsPath string, metrics MetricsSource) *HttpServer {
+	hs := &HttpServer{
+		config:        config,
+		metricsPath:   metricsPath,
+		metrics:       metrics,
+		healthManager: NewHealthManager(version.Version, logrus.StandardLogger()),
+		tls:           NewTLSManager(),
+		auth:          NewAuthenticator(),
+		version:       version.Version,
+		serveErr:      make(chan error, 1),
+		stopped:       make(chan struct{}),
 	}
+	hs.metricsHandler = newMetricsHandler(hs.gatherers(metrics))
+	return hs
+}
+
+// gatherers 完整抓取输出的指标：收集器指标、HTTP 层自身指标和健康状态
+func (hs *HttpServer) gatherers(metrics MetricsSource) prometheus.Gatherers {
+	return prometheus.Gatherers{metrics.GetGatherer(), httpRegistry, hs.healthManager.Gatherer()}
 }
 
 // Setup 设置HTTP服务器
 func (hs *HttpServer) Setup(metricsManager *MetricsManager) error {
-	// 设置HTTP多路复用器
-	mux := http.NewServeMux()
-
-	// 注册指标端点
-	mux.Handle(hs.metricsPath, hs)
-
 	// 设置限流中间件
 	if *UseRatelimit {
 		rateLimiter, err := ratelimit.NewRateLimiter(*rateLimitInterval, *rateLimitSize)
@@ -69,48 +107,137 @@ func (hs *HttpServer) Setup(metricsManager *MetricsManager) error {
 	// panic recovery 改为在 ServeHTTP 顶层统一处理
 
 	// 设置健康检查
-	if err := hs.setupHealthCheck(mux); err != nil {
+	if err := hs.setupHealthCheck(); err != nil {
 		return err
 	}
 
-	// 设置地址
-	addr := fmt.Sprintf("%s:%d", hs.config.Address, hs.config.Port)
+	// 加载 TLS 与认证配置
+	webConfig, err := LoadWebConfig(hs.config.WebConfigFile)
+	if err != nil {
+		return err
+	}
+	if err := hs.tls.Apply(webConfig.TLSServerConfig); err != nil {
+		return err
+	}
+	authState, err := hs.auth.Load(webConfig)
+	if err != nil {
+		return err
+	}
+	hs.auth.Set(authState)
+
+	// 设置按客户端、路由的限流和并发抓取上限
+	limiter, err := newRequestLimiter(hs.config.Limits)
+	if err != nil {
+		return err
+	}
+	hs.limiter = limiter
+
+	// 设置HTTP多路复用器
+	mux, err := hs.buildMux(hs.metricsPath)
+	if err != nil {
+		return err
+	}
+	hs.mux = mux
+
+	// 输出监听地址，Run 时绑定
 	schema := "http"
-	fmt.Fprintf(os.Stdout, "Listening and serving on [%s://%s]\n", schema, addr)
+	if hs.tls.Enabled() {
+		schema = "https"
+	}
+	for _, spec := range hs.config.ListenAddresses() {
+		address := spec.Address
+		if network, addr, err := spec.Parse(); err == nil && network == exporter.ListenTCP {
+			address = schema + "://" + addr
+		}
+		fmt.Fprintf(os.Stdout, "Listening and serving on [%s]\n", address)
+	}
+	return nil
+}
 
-	// 创建HTTP服务器
-	hs.server = &http.Server{
-		Addr:              addr,
-		Handler:           mux,
-		ReadTimeout:       10 * time.Second,
-		ReadHeaderTimeout: 5 * time.Second,
-		WriteTimeout:      20 * time.Second,
-		IdleTimeout:       60 * time.Second,
-		MaxHeaderBytes:    1 << 20, // 1MB
+// buildMux 创建包含指标、健康检查、着陆页和 favicon 的路由
+// 所有路由都经过限流，启用认证时除 /live 和 favicon 外的路由都需要认证；metricsPath 为空时不提供指标端点
+func (hs *HttpServer) buildMux(metricsPath string) (*http.ServeMux, error) {
+	mux := http.NewServeMux()
+
+	// 注册指标端点
+	if metricsPath != "" {
+		mux.Handle(metricsPath, hs.protected(hs))
 	}
 
+	// 注册按命名空间/设备探测的端点
+	mux.Handle("/probe", hs.protected(http.HandlerFunc(hs.serveProbe)))
+
+	// 注册收集器管理和拓扑 API
+	hs.registerAdminRoutes(mux)
+
+	// 注册健康检查端点
+	hs.registerHealthRoutes(mux)
+
 	// 设置着陆页
-	if err := hs.setupLandingPage(mux); err != nil {
-		return err
+	if err := hs.setupLandingPage(mux, metricsPath); err != nil {
+		return nil, err
 	}
 
 	// 设置favicon
 	favicon := NewFavicon()
-	mux.Handle("/favicon.ico", favicon)
+	mux.Handle("/favicon.ico", hs.public(favicon))
+	return mux, nil
+}
 
-	logrus.Infof("HTTP server is running on %s", addr)
-	return nil
+// protected 为路由加上限流和认证
+func (hs *HttpServer) protected(handler http.Handler) http.Handler {
+	return Chain(handler, hs.limitRequest, hs.auth.Middleware())
+}
+
+// public 为公开路由加上限流
+func (hs *HttpServer) public(handler http.Handler) http.Handler {
+	return Chain(handler, hs.limitRequest)
+}
+
+// limitRequest 使用当前生效的限制器执行按客户端和路由的限流
+func (hs *HttpServer) limitRequest(req *Request) {
+	hs.mu.RLock()
+	limiter := hs.limiter
+	hs.mu.RUnlock()
+	if limiter != nil {
+		limiter.Middleware()(req)
+	}
+}
+
+// newServer 创建为监听地址 addr 提供服务的 http.Server，路由通过 dispatch 间接访问以支持热重载
+func (hs *HttpServer) newServer(addr string) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           http.HandlerFunc(hs.dispatch),
+		ReadTimeout:       10 * time.Second,
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      20 * time.Second,
+		IdleTimeout:       60 * time.Second,
+		MaxHeaderBytes:    1 << 20, // 1MB
+	}
+}
+
+// dispatch 将请求交给当前生效的路由
+func (hs *HttpServer) dispatch(w http.ResponseWriter, r *http.Request) {
+	hs.mu.RLock()
+	mux := hs.mux
+	hs.mu.RUnlock()
+	mux.ServeHTTP(w, r)
 }
 
 // setupLandingPage 设置着陆页
-func (hs *HttpServer) setupLandingPage(mux *http.ServeMux) error {
+func (hs *HttpServer) setupLandingPage(mux *http.ServeMux, metricsPath string) error {
+	var links []LandingPageLinks
+	if metricsPath != "" {
+		links = append(links, LandingPageLinks{Text: "Metrics", Address: metricsPath})
+	}
 	landConfig := LandingPageConfig{
 		Name:    "TC Exporter",
-		Version: "1.0.0",
-		Links: []LandingPageLinks{
+		Version: hs.version,
+		Links: append(links, []LandingPageLinks{
 			{
-				Text:    "Metrics",
-				Address: hs.metricsPath,
+				Text:    "Probe",
+				Address: "probe?netns=" + tc.DefaultNetNS,
 			},
 			{
 				Text:    "Health",
@@ -124,7 +251,7 @@ func (hs *HttpServer) setupLandingPage(mux *http.ServeMux) error {
 				Text:    "Live",
 				Address: "live",
 			},
-		},
+		}...),
 	}
 
 	landPage, err := NewLandingPage(landConfig)
@@ -139,9 +266,7 @@ func (hs *HttpServer) setupLandingPage(mux *http.ServeMux) error {
 		return customErr
 	}
 
-	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		landPage.ServeHTTP(w, r)
-	})
+	mux.Handle("/", hs.protected(landPage))
 
 	return nil
 }
@@ -164,13 +289,57 @@ func (hs *HttpServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// 处理指标请求 - 使用更安全的 promhttp 选项
-	handler := promhttp.HandlerFor(hs.promReg, promhttp.HandlerOpts{
+	// 限制并发抓取数，并发到达的抓取共享同一次收集
+	hs.mu.RLock()
+	metrics, metricsHandler := hs.metrics, hs.metricsHandler
+	hs.mu.RUnlock()
+	release, ok := hs.beginScrape(req)
+	if !ok {
+		return
+	}
+	defer release()
+
+	// collect[]/exclude[] 只运行所选的收集器
+	query := r.URL.Query()
+	include, exclude := query["collect[]"], query["exclude[]"]
+	if len(include) > 0 || len(exclude) > 0 {
+		filtered, err := metrics.FilteredGatherer(include, exclude)
+		if err != nil {
+			req.Error = err
+			req.Fail(http.StatusBadRequest)
+			return
+		}
+		metricsHandler = newMetricsHandler(filtered)
+	}
+	metricsHandler.ServeHTTP(w, r)
+}
+
+// beginScrape 占用一个抓取并发名额，达到上限时返回 503 和 false
+func (hs *HttpServer) beginScrape(req *Request) (func(), bool) {
+	hs.mu.RLock()
+	limiter := hs.limiter
+	hs.mu.RUnlock()
+	if limiter != nil && !limiter.acquireScrape() {
+		reject(req, rejectMaxInFlight, http.StatusServiceUnavailable, ratelimit.ErrRateLimited)
+		return nil, false
+	}
+	scrapesInFlight.Inc()
+	return func() {
+		scrapesInFlight.Dec()
+		if limiter != nil {
+			limiter.releaseScrape()
+		}
+	}, true
+}
+
+// newMetricsHandler 创建指标端点处理器
+func newMetricsHandler(gatherer prometheus.Gatherer) http.Handler {
+	// 使用更安全的 promhttp 选项
+	return promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{
 		EnableOpenMetrics: true,
 		ErrorLog:          logrus.StandardLogger(),
 		// ErrorHandling 可按需要改为 ContinueOnError 以便部分指标失败时仍返回其余指标
 	})
-	handler.ServeHTTP(w, r)
 }
 
 // Use 添加中间件处理器
@@ -189,36 +358,234 @@ func (hs *HttpServer) createRequest(w http.ResponseWriter, r *http.Request) *Req
 	return req
 }
 
-// Run 启动HTTP服务器
+// Run 在所有监听地址上启动HTTP服务器，阻塞直到服务器停止或监听出错
+// 热重载更换监听地址时 Run 不会返回
 func (hs *HttpServer) Run() error {
-	if hs.server == nil {
+	hs.mu.Lock()
+	if hs.mux == nil {
+		hs.mu.Unlock()
 		return fmt.Errorf("HTTP server not initialized")
 	}
+	servers, err := hs.bind(hs.config.ListenAddresses(), nil)
+	if err != nil {
+		hs.mu.Unlock()
+		return err
+	}
+	hs.servers = servers
+	hs.running = true
+	hs.mu.Unlock()
 
-	logrus.Infof("Running HTTP server on %s", hs.server.Addr)
-	if err := hs.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		customErr := errors.Wrap(err, errors.ErrCodeServerRun, "HTTP server listen and serve failed")
-		customErr.WithContext("address", hs.server.Addr)
-		logrus.WithFields(logrus.Fields{
-			"error_code": customErr.Code,
-			"error":      customErr.Error(),
-			"address":    hs.server.Addr,
-		}).Error("HTTP server listen and serve failed")
-		return customErr
+	select {
+	case err := <-hs.serveErr:
+		return err
+	case <-hs.stopped:
+		return nil
+	}
+}
+
+// bind 为 specs 中的每个监听地址创建 http.Server 并开始服务，current 中已有的地址沿用原来的服务器
+// 任一地址绑定失败时关闭本次新建的监听并返回错误
+func (hs *HttpServer) bind(specs []exporter.ListenConfig, current []*listenerServer) ([]*listenerServer, error) {
+	existing := make(map[string]*listenerServer, len(current))
+	for _, ls := range current {
+		existing[ls.key] = ls
+	}
+
+	type pending struct {
+		ls  *listenerServer
+		lns []net.Listener
+	}
+	var (
+		servers []*listenerServer
+		started []pending
+	)
+	closeStarted := func() {
+		for _, p := range started {
+			for _, ln := range p.lns {
+				ln.Close()
+			}
+		}
+	}
+	for _, spec := range specs {
+		key, err := listenKey(spec)
+		if err != nil {
+			closeStarted()
+			return nil, listenError(err, spec.Address)
+		}
+		if ls, ok := existing[key]; ok {
+			// 同一个 Unix 套接字只更新权限和属主，不重新绑定
+			if ls.spec != spec && strings.HasPrefix(key, exporter.ListenUnix+"://") {
+				if err := applySocketPermissions(strings.TrimPrefix(key, exporter.ListenUnix+"://"), spec); err != nil {
+					closeStarted()
+					return nil, listenError(err, spec.Address)
+				}
+			}
+			servers = append(servers, &listenerServer{key: key, spec: spec, server: ls.server})
+			continue
+		}
+		lns, err := hs.listen(spec)
+		if err != nil {
+			closeStarted()
+			return nil, err
+		}
+		ls := &listenerServer{key: key, spec: spec, server: hs.newServer(spec.Address)}
+		servers = append(servers, ls)
+		started = append(started, pending{ls: ls, lns: lns})
+	}
+
+	for _, p := range started {
+		for _, ln := range p.lns {
+			logrus.Infof("Running HTTP server on %s (%s)", p.ls.spec.Address, ln.Addr())
+			hs.serve(p.ls.server, ln)
+		}
+	}
+	return servers, nil
+}
+
+// serve 在后台为 srv 提供服务，非正常退出时通过 serveErr 通知 Run
+func (hs *HttpServer) serve(srv *http.Server, ln net.Listener) {
+	go func() {
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			customErr := errors.Wrap(err, errors.ErrCodeServerRun, "HTTP server serve failed")
+			customErr.WithContext("address", srv.Addr)
+			logrus.WithFields(logrus.Fields{
+				"error_code": customErr.Code,
+				"error":      customErr.Error(),
+				"address":    srv.Addr,
+			}).Error("HTTP server serve failed")
+			select {
+			case hs.serveErr <- customErr:
+			default:
+			}
+		}
+	}()
+}
+
+// MetricsGatherer 返回当前生效的收集器 Gatherer，热重载后随 MetricsSource 更新
+func (hs *HttpServer) MetricsGatherer() prometheus.Gatherer {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+	return hs.metrics.GetGatherer()
+}
+
+// SetMetricsSource 替换指标端点的数据来源，完整抓取同时输出 HTTP 层自身指标和健康状态
+func (hs *HttpServer) SetMetricsSource(metrics MetricsSource) {
+	handler := newMetricsHandler(hs.gatherers(metrics))
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	hs.metrics = metrics
+	hs.metricsHandler = handler
+}
+
+// ApplyConfig 应用新的 HTTP 配置
+// 先加载 Web 配置、证书和认证令牌；指标路径变化时重建路由；监听地址变化时先绑定新增的地址，
+// 成功后再优雅关闭移除的监听，因此失败时不会改变任何运行状态
+func (hs *HttpServer) ApplyConfig(cfg exporter.Config) error {
+	hs.mu.RLock()
+	oldPath, running, oldServers := hs.metricsPath, hs.running, hs.servers
+	oldLimits, limiter := hs.config.Limits, hs.limiter
+	hs.mu.RUnlock()
+
+	webConfig, err := LoadWebConfig(cfg.WebConfigFile)
+	if err != nil {
+		return err
+	}
+	tlsState, err := hs.tls.Load(webConfig.TLSServerConfig)
+	if err != nil {
+		return err
+	}
+	authState, err := hs.auth.Load(webConfig)
+	if err != nil {
+		return err
+	}
+	// 限制配置未变化时保留现有的令牌桶和并发计数，否则每次重载都会清空客户端的限流状态
+	if !reflect.DeepEqual(oldLimits, cfg.Limits) {
+		if limiter, err = newRequestLimiter(cfg.Limits); err != nil {
+			return err
+		}
+	}
+
+	var mux *http.ServeMux
+	if cfg.ScrapePath() != oldPath {
+		if mux, err = hs.buildMux(cfg.ScrapePath()); err != nil {
+			return err
+		}
+	}
+
+	// 绑定成功后新增的监听立即开始服务，路由通过 dispatch 读取，在下方切换后生效
+	newServers := oldServers
+	if running {
+		if newServers, err = hs.bind(cfg.ListenAddresses(), oldServers); err != nil {
+			return err
+		}
+	}
+
+	hs.healthManager.SetConfig(cfg.Health)
+	hs.mu.Lock()
+	hs.config = cfg
+	hs.limiter = limiter
+	if mux != nil {
+		hs.mux = mux
+		hs.metricsPath = cfg.ScrapePath()
+		logrus.Infof("Metrics path changed from %q to %q", oldPath, cfg.ScrapePath())
+	}
+	hs.servers = newServers
+	hs.mu.Unlock()
+	hs.tls.Set(tlsState)
+	hs.auth.Set(authState)
+
+	kept := make(map[*http.Server]bool, len(newServers))
+	for _, ls := range newServers {
+		kept[ls.server] = true
+	}
+	for _, ls := range oldServers {
+		if !kept[ls.server] {
+			logrus.Infof("Closing HTTP listener %s", ls.spec.Address)
+			go hs.shutdown(ls.server)
+		}
 	}
 	return nil
 }
 
-// Stop 停止HTTP服务器
+// Stop 停止HTTP服务器，所有监听并行关闭
 func (hs *HttpServer) Stop() error {
-	if hs.server == nil {
+	hs.stopOnce.Do(func() {
+		close(hs.stopped)
+	})
+	hs.mu.RLock()
+	servers := hs.servers
+	hs.mu.RUnlock()
+	if len(servers) == 0 {
 		return nil
 	}
 
 	logrus.Info("Stopping HTTP server")
+	errs := make([]error, len(servers))
+	var wg sync.WaitGroup
+	for i, ls := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = hs.shutdown(ls.server)
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	logrus.Info("HTTP server gracefully stopped")
+	return nil
+}
 
+// shutdown 在配置的超时时间内优雅关闭 srv
+func (hs *HttpServer) shutdown(srv *http.Server) error {
 	// 使用配置中的关闭超时时间
+	hs.mu.RLock()
 	shutdownTimeout := hs.config.Server.ShutdownTimeout
+	hs.mu.RUnlock()
 	if shutdownTimeout == 0 {
 		shutdownTimeout = 30 * time.Second // 默认30秒
 	}
@@ -226,9 +593,9 @@ func (hs *HttpServer) Stop() error {
 	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
 
-	logrus.Infof("HTTP server shutdown timeout set to: %v", shutdownTimeout)
+	logrus.Infof("HTTP server %s shutdown timeout set to: %v", srv.Addr, shutdownTimeout)
 
-	if err := hs.server.Shutdown(ctx); err != nil {
+	if err := srv.Shutdown(ctx); err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			logrus.Warnf("HTTP server shutdown timed out after %v", shutdownTimeout)
 		} else {
@@ -242,32 +609,43 @@ func (hs *HttpServer) Stop() error {
 		}
 		return err
 	}
-
-	logrus.Info("HTTP server gracefully stopped")
 	return nil
 }
 
-// setupHealthCheck 设置健康检查端点
-func (hs *HttpServer) setupHealthCheck(mux *http.ServeMux) error {
-	// 创建健康管理器
-	hs.healthManager = NewHealthManager(hs.version, logrus.StandardLogger())
-
-	// 注册健康检查器
-	hs.healthManager.RegisterChecker(NewTCHealthChecker(logrus.StandardLogger()))
-	hs.healthManager.RegisterChecker(NewMetricsHealthChecker(logrus.StandardLogger()))
+// setupHealthCheck 注册健康检查器，端点由 registerHealthRoutes 注册
+func (hs *HttpServer) setupHealthCheck() error {
+	hs.healthManager.SetConfig(hs.config.Health)
 
-	// 注册健康检查端点
-	mux.HandleFunc("/health", hs.healthManager.HealthHandler)
-	mux.HandleFunc("/ready", hs.healthManager.ReadyHandler)
-	mux.HandleFunc("/live", hs.healthManager.LivenessHandler)
+	// 注册健康检查器，默认命名空间的 netlink 和指标收集为关键检查，其余命名空间失败只降级
+	hs.healthManager.RegisterChecker(NewTCHealthChecker(logrus.StandardLogger()), true)
+	hs.healthManager.RegisterChecker(NewMetricsHealthChecker(logrus.StandardLogger(), hs.collectorManager, hs.healthManager.Config), true)
+	hs.healthManager.RegisterChecker(NewNamespaceHealthChecker(logrus.StandardLogger()), false)
 
-	// 设置服务为就绪状态
+	// 服务可以接收请求，首次成功收集完成后 /ready 才返回就绪
+	hs.healthManager.SetReadyCheck(func() bool {
+		manager := hs.collectorManager()
+		return manager != nil && !manager.CollectionHealth().LastSuccess.IsZero()
+	})
 	hs.healthManager.SetReady(true)
-
-	logrus.Info("Health check endpoints registered: /health, /ready, /live")
 	return nil
 }
 
+// RegisterHealthChecker 注册额外的健康检查器，需要在 Run 之前调用
+func (hs *HttpServer) RegisterHealthChecker(checker HealthChecker, critical bool) {
+	hs.healthManager.RegisterChecker(checker, critical)
+}
+
+// registerHealthRoutes 注册健康检查端点
+func (hs *HttpServer) registerHealthRoutes(mux *http.ServeMux) {
+	mux.Handle("/health", hs.protected(http.HandlerFunc(hs.healthManager.HealthHandler)))
+	mux.Handle("/health/{checker}", hs.protected(http.HandlerFunc(hs.healthManager.CheckerHandler)))
+	mux.Handle("/ready", hs.protected(http.HandlerFunc(hs.healthManager.ReadyHandler)))
+	// 存活探针保持公开，供 systemd/kubelet 等无凭据的探测使用
+	mux.Handle("/live", hs.public(http.HandlerFunc(hs.healthManager.LivenessHandler)))
+
+	logrus.Info("Health check endpoints registered: /health, /health/{checker}, /ready, /live")
+}
+
 // SetReady 设置服务就绪状态
 func (hs *HttpServer) SetReady(ready bool) {
 	if hs.healthManager != nil {
@@ -275,7 +653,12 @@ func (hs *HttpServer) SetReady(ready bool) {
 	}
 }
 
-// GetServer 获取HTTP服务器实例
+// GetServer 获取第一个监听地址的HTTP服务器实例，Run 之前返回 nil
 func (hs *HttpServer) GetServer() *http.Server {
-	return hs.server
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+	if len(hs.servers) == 0 {
+		return nil
+	}
+	return hs.servers[0].server
 }
@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"testing"
+
+	"gitee.com/openeuler/uos-tc-exporter/internal/exporter"
+)
+
+func TestApplyConfigKeepsLimiter(t *testing.T) {
+	cfg := exporter.DefaultConfig
+	cfg.Limits.MaxInFlightScrapes = 2
+	hs := NewHttpServer(cfg, cfg.ScrapePath(), NewMetricsManager(cfg, nil))
+	limiter, err := newRequestLimiter(cfg.Limits)
+	if err != nil {
+		t.Fatalf("newRequestLimiter() error = %v", err)
+	}
+	hs.limiter = limiter
+
+	// 与限制无关的修改保留现有的限流状态
+	changed := cfg
+	changed.Logging.Level = "debug"
+	if err := hs.ApplyConfig(changed); err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	if hs.limiter != limiter {
+		t.Error("limiter replaced although limits did not change")
+	}
+
+	changed.Limits.MaxInFlightScrapes = 4
+	if err := hs.ApplyConfig(changed); err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	if hs.limiter == limiter {
+		t.Error("limiter kept although limits changed")
+	}
+}
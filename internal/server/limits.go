@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"gitee.com/openeuler/uos-tc-exporter/internal/exporter"
+	"gitee.com/openeuler/uos-tc-exporter/pkg/errors"
+	"gitee.com/openeuler/uos-tc-exporter/pkg/ratelimit"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// 请求被拒绝的原因，作为 tc_exporter_http_requests_rejected_total 的 reason 标签
+const (
+	rejectGlobalRateLimit = "rate_limit"
+	rejectClientRateLimit = "client_rate_limit"
+	rejectRouteRateLimit  = "route_rate_limit"
+	rejectMaxInFlight     = "max_in_flight"
+)
+
+var (
+	// requestsRejected 被限流或并发上限拒绝的请求数
+	requestsRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "tc_exporter_http_requests_rejected_total",
+		Help: "Total number of HTTP requests rejected by rate or in-flight limits, by reason.",
+	}, []string{"reason"})
+	// scrapesInFlight 正在处理的指标抓取请求数
+	scrapesInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "tc_exporter_http_scrapes_in_flight",
+		Help: "Number of metrics scrapes currently being served.",
+	})
+	// httpRegistry HTTP 层自身指标的注册表，不随热重载重建
+	httpRegistry = prometheus.NewRegistry()
+)
+
+func init() {
+	httpRegistry.MustRegister(requestsRejected, scrapesInFlight)
+	for _, reason := range []string{rejectGlobalRateLimit, rejectClientRateLimit, rejectRouteRateLimit, rejectMaxInFlight} {
+		requestsRejected.WithLabelValues(reason)
+	}
+}
+
+// requestLimiter 按客户端 IP、路由限流并限制并发抓取数，加载后不再修改，热重载时整体替换
+type requestLimiter struct {
+	clients  *ratelimit.KeyedRateLimiter
+	routes   map[string]*ratelimit.TokenBucket
+	inFlight chan struct{}
+}
+
+// newRequestLimiter 根据配置创建限制器，未配置的限制项为 nil
+func newRequestLimiter(cfg exporter.LimitsConfig) (*requestLimiter, error) {
+	rl := &requestLimiter{routes: make(map[string]*ratelimit.TokenBucket)}
+	if cfg.PerClient.Burst > 0 {
+		clients, err := ratelimit.NewKeyedRateLimiter(cfg.PerClient.Interval, cfg.PerClient.Burst, cfg.MaxClients)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeRateLimit, "failed to create per-client rate limiter").
+				WithContext("interval", cfg.PerClient.Interval).WithContext("burst", cfg.PerClient.Burst)
+		}
+		rl.clients = clients
+	}
+	for route, rate := range cfg.Routes {
+		if rate.Burst == 0 {
+			continue
+		}
+		bucket, err := ratelimit.NewTokenBucket(rate.Interval, rate.Burst)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeRateLimit, "failed to create route rate limiter").
+				WithContext("route", route)
+		}
+		rl.routes[route] = bucket
+	}
+	if cfg.MaxInFlightScrapes > 0 {
+		rl.inFlight = make(chan struct{}, cfg.MaxInFlightScrapes)
+	}
+	return rl, nil
+}
+
+// Middleware 返回按客户端和路由限流的中间件，先于认证执行以限制暴力尝试
+func (rl *requestLimiter) Middleware() HandlerFunc {
+	return func(req *Request) {
+		if bucket, ok := rl.routes[req.Request.URL.Path]; ok {
+			if err := bucket.Get(); err != nil {
+				reject(req, rejectRouteRateLimit, http.StatusTooManyRequests, err)
+				return
+			}
+		}
+		if rl.clients != nil {
+			if err := rl.clients.Get(clientIP(req.Request)); err != nil {
+				reject(req, rejectClientRateLimit, http.StatusTooManyRequests, err)
+			}
+		}
+	}
+}
+
+// acquireScrape 占用一个抓取并发名额，达到上限时返回 false
+func (rl *requestLimiter) acquireScrape() bool {
+	if rl.inFlight == nil {
+		return true
+	}
+	select {
+	case rl.inFlight <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// releaseScrape 释放 acquireScrape 占用的名额
+func (rl *requestLimiter) releaseScrape() {
+	if rl.inFlight != nil {
+		<-rl.inFlight
+	}
+}
+
+// reject 记录并返回被拒绝的请求
+func reject(req *Request, reason string, status int, err error) {
+	requestsRejected.WithLabelValues(reason).Inc()
+	customErr := errors.Wrap(err, errors.ErrCodeRateLimit, "request rejected")
+	customErr.WithContext("reason", reason).WithContext("remote_addr", req.Request.RemoteAddr)
+	logrus.WithFields(logrus.Fields{
+		"error_code":  customErr.Code,
+		"reason":      reason,
+		"remote_addr": req.Request.RemoteAddr,
+		"path":        req.Request.URL.Path,
+	}).Debug("HTTP request rejected")
+	req.Error = fmt.Errorf("%s: %s", http.StatusText(status), reason)
+	req.Fail(status)
+}
+
+// clientIP 返回请求的来源 IP，不信任 X-Forwarded-For 等可伪造的请求头
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
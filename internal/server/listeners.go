@@ -0,0 +1,248 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"gitee.com/openeuler/uos-tc-exporter/internal/exporter"
+	"gitee.com/openeuler/uos-tc-exporter/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// systemdListenFDsStart systemd socket activation 传入的第一个文件描述符
+const systemdListenFDsStart = 3
+
+// listenerServer 单个监听地址及为其提供服务的 http.Server
+type listenerServer struct {
+	// key 规范化后的 network://address，用于热重载时比较监听地址
+	key    string
+	spec   exporter.ListenConfig
+	server *http.Server
+}
+
+// activatedListener systemd 传入的监听套接字
+type activatedListener struct {
+	name  string
+	ln    net.Listener
+	taken bool
+}
+
+var (
+	activationOnce sync.Once
+	activationMu   sync.Mutex
+	activated      []*activatedListener
+	activationErr  error
+)
+
+// listenKey 返回监听地址的规范化键
+func listenKey(spec exporter.ListenConfig) (string, error) {
+	network, address, err := spec.Parse()
+	if err != nil {
+		return "", err
+	}
+	return network + "://" + address, nil
+}
+
+// listen 按监听配置创建监听，systemd:// 可能返回多个监听
+func (hs *HttpServer) listen(spec exporter.ListenConfig) ([]net.Listener, error) {
+	network, address, err := spec.Parse()
+	if err != nil {
+		return nil, listenError(err, spec.Address)
+	}
+	var lns []net.Listener
+	switch network {
+	case exporter.ListenUnix:
+		ln, err := listenUnix(address, spec)
+		if err != nil {
+			return nil, listenError(err, spec.Address)
+		}
+		lns = []net.Listener{ln}
+	case exporter.ListenSystemd:
+		if lns, err = takeActivatedListeners(address); err != nil {
+			return nil, listenError(err, spec.Address)
+		}
+	default:
+		ln, err := net.Listen("tcp", address)
+		if err != nil {
+			return nil, listenError(err, spec.Address)
+		}
+		lns = []net.Listener{ln}
+	}
+	for i, ln := range lns {
+		lns[i] = hs.tls.WrapListener(ln)
+	}
+	return lns, nil
+}
+
+// listenError 包装监听失败的错误并记录日志
+func listenError(err error, address string) error {
+	customErr := errors.Wrap(err, errors.ErrCodeServerRun, "HTTP server listen failed")
+	customErr.WithContext("address", address)
+	logrus.WithFields(logrus.Fields{
+		"error_code": customErr.Code,
+		"error":      customErr.Error(),
+		"address":    address,
+	}).Error("HTTP server listen failed")
+	return customErr
+}
+
+// listenUnix 在 path 上创建 Unix 套接字，删除上次运行遗留的套接字文件，并设置权限和属主
+func listenUnix(path string, spec exporter.ListenConfig) (net.Listener, error) {
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		// 仍有进程在监听时拒绝删除
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("%s is in use by another process", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("remove stale socket %s: %w", path, err)
+		}
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := applySocketPermissions(path, spec); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return ln, nil
+}
+
+// applySocketPermissions 设置 Unix 套接字文件的权限和属主，未配置的项保持不变
+func applySocketPermissions(path string, spec exporter.ListenConfig) error {
+	mode, err := spec.FileMode()
+	if err != nil {
+		return err
+	}
+	if spec.SocketMode != "" {
+		if err := os.Chmod(path, mode); err != nil {
+			return fmt.Errorf("chmod socket %s: %w", path, err)
+		}
+	}
+	if spec.SocketOwner == "" && spec.SocketGroup == "" {
+		return nil
+	}
+	uid, gid := -1, -1
+	if spec.SocketOwner != "" {
+		if uid, err = lookupID(spec.SocketOwner, func(name string) (string, error) {
+			u, err := user.Lookup(name)
+			if err != nil {
+				return "", err
+			}
+			return u.Uid, nil
+		}); err != nil {
+			return fmt.Errorf("invalid socket_owner %q: %w", spec.SocketOwner, err)
+		}
+	}
+	if spec.SocketGroup != "" {
+		if gid, err = lookupID(spec.SocketGroup, func(name string) (string, error) {
+			g, err := user.LookupGroup(name)
+			if err != nil {
+				return "", err
+			}
+			return g.Gid, nil
+		}); err != nil {
+			return fmt.Errorf("invalid socket_group %q: %w", spec.SocketGroup, err)
+		}
+	}
+	if err := os.Chown(path, uid, gid); err != nil {
+		return fmt.Errorf("chown socket %s: %w", path, err)
+	}
+	return nil
+}
+
+// lookupID 将数字 ID 或名称解析为 ID
+func lookupID(value string, lookup func(string) (string, error)) (int, error) {
+	if id, err := strconv.Atoi(value); err == nil {
+		return id, nil
+	}
+	id, err := lookup(value)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(id)
+}
+
+// takeActivatedListeners 取出 systemd 传入的、名称为 name（为空时不限）且尚未使用的监听套接字
+// 传入的套接字只能取出一次，关闭后无法在热重载时重新获取
+func takeActivatedListeners(name string) ([]net.Listener, error) {
+	activationOnce.Do(func() {
+		activated, activationErr = loadActivatedListeners()
+	})
+	if activationErr != nil {
+		return nil, activationErr
+	}
+	activationMu.Lock()
+	defer activationMu.Unlock()
+	var lns []net.Listener
+	for _, a := range activated {
+		if a.taken || (name != "" && a.name != name) {
+			continue
+		}
+		a.taken = true
+		lns = append(lns, a.ln)
+	}
+	if len(lns) == 0 {
+		if name == "" {
+			return nil, fmt.Errorf("no unused socket passed by systemd socket activation")
+		}
+		return nil, fmt.Errorf("no unused socket named %q passed by systemd socket activation", name)
+	}
+	return lns, nil
+}
+
+// loadActivatedListeners 按 sd_listen_fds(3) 的约定读取 LISTEN_PID、LISTEN_FDS 和 LISTEN_FDNAMES，
+// 读取后清除这些环境变量，避免子进程误用
+func loadActivatedListeners() ([]*activatedListener, error) {
+	pid, fds := os.Getenv("LISTEN_PID"), os.Getenv("LISTEN_FDS")
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+	if pid == "" || fds == "" {
+		return nil, fmt.Errorf("LISTEN_FDS not set, the exporter was not started by systemd socket activation")
+	}
+	if pid != strconv.Itoa(os.Getpid()) {
+		return nil, fmt.Errorf("LISTEN_PID %s does not match the exporter pid %d", pid, os.Getpid())
+	}
+	count, err := strconv.Atoi(fds)
+	if err != nil || count <= 0 {
+		return nil, fmt.Errorf("invalid LISTEN_FDS %q", fds)
+	}
+
+	listeners := make([]*activatedListener, 0, count)
+	for i := 0; i < count; i++ {
+		fd := systemdListenFDsStart + i
+		syscall.CloseOnExec(fd)
+		name := ""
+		if i < len(names) {
+			name = names[i]
+		}
+		file := os.NewFile(uintptr(fd), "systemd-listen-fd-"+strconv.Itoa(fd))
+		ln, err := net.FileListener(file)
+		file.Close()
+		if err != nil {
+			for _, l := range listeners {
+				l.ln.Close()
+			}
+			return nil, fmt.Errorf("socket activation fd %d (%s) is not a stream listener: %w", fd, name, err)
+		}
+		logrus.Infof("Using socket passed by systemd: fd=%d name=%s address=%s", fd, name, ln.Addr())
+		listeners = append(listeners, &activatedListener{name: name, ln: ln})
+	}
+	return listeners, nil
+}
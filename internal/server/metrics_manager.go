@@ -4,24 +4,44 @@
 package server
 
 import (
+	"fmt"
+	"time"
+
 	tc_collector "gitee.com/openeuler/uos-tc-exporter/internal/collectors"
+	"gitee.com/openeuler/uos-tc-exporter/internal/exporter"
 	"gitee.com/openeuler/uos-tc-exporter/internal/metrics"
+	"gitee.com/openeuler/uos-tc-exporter/internal/metrics/collectors/app"
 	_ "gitee.com/openeuler/uos-tc-exporter/internal/metrics/collectors/qdisc"
+	"gitee.com/openeuler/uos-tc-exporter/internal/metrics/collectors/sampler"
+	"gitee.com/openeuler/uos-tc-exporter/pkg/errors"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/sirupsen/logrus"
 )
 
+var (
+	probeSuccessDesc = prometheus.NewDesc("tc_exporter_probe_success",
+		"Whether the scoped probe succeeded", nil, nil)
+	probeDurationDesc = prometheus.NewDesc("tc_exporter_probe_duration_seconds",
+		"Duration of the scoped probe in seconds", nil, nil)
+)
+
 // MetricsManager 负责指标管理
 type MetricsManager struct {
 	promReg *prometheus.Registry
+	config  exporter.Config
+	manager *metrics.ManagerV2
+	// configStats 配置管理器统计信息，供 app 收集器输出重载次数和配置哈希
+	configStats app.StatsFunc
 }
 
 // NewMetricsManager 创建新的指标管理器
-func NewMetricsManager() *MetricsManager {
+func NewMetricsManager(config exporter.Config, configStats app.StatsFunc) *MetricsManager {
 	return &MetricsManager{
-		promReg: prometheus.NewRegistry(),
+		promReg:     prometheus.NewRegistry(),
+		config:      config,
+		configStats: configStats,
 	}
 }
 
@@ -40,27 +60,148 @@ func (mm *MetricsManager) Setup() {
 	// 注册自定义指标
 	// exporter.RegisterPrometheus(mm.promReg)
 	// mm.promReg.MustRegister(tc_collector.NewTcCollector())
-	mng := metrics.NewManagerV2(nil, logrus.StandardLogger())
+	managerConfig := mm.config.Monitoring
+	managerConfig.Collectors = mm.config.Collectors
+	mng := metrics.NewManagerV2(&managerConfig, logrus.StandardLogger())
+	if managerConfig.Enabled {
+		mm.setupApp(mng)
+	}
+	mm.setupSampler(mng)
+	mm.manager = mng
 	tcCollector := tc_collector.CollectorFunc(mng.CollectAll)
 	mm.promReg.MustRegister(tcCollector)
+	if managerConfig.Enabled {
+		mm.promReg.MustRegister(mng.SelfMetrics())
+	}
 	logrus.Info("Metrics registry setup completed")
 }
 
+// WarmUp 执行一次完整收集并丢弃结果，使 /ready 不必等待第一次抓取
+func (mm *MetricsManager) WarmUp() {
+	if mm.manager == nil {
+		return
+	}
+	ch := make(chan prometheus.Metric, 256)
+	done := make(chan struct{})
+	go func() {
+		for range ch {
+		}
+		close(done)
+	}()
+	mm.manager.CollectAll(ch)
+	close(ch)
+	<-done
+	if health := mm.manager.CollectionHealth(); health.LastError != nil && health.LastSuccess.IsZero() {
+		logrus.Warnf("Warm-up collection failed: %v", health.LastError)
+		return
+	}
+	logrus.Info("Warm-up collection finished")
+}
+
+// setupApp 注册应用信息收集器
+func (mm *MetricsManager) setupApp(mng *metrics.ManagerV2) {
+	cfg, err := mng.ResolveCollectorConfig("app")
+	if err != nil {
+		logrus.Warnf("Failed to resolve config for app collector: %v", err)
+		return
+	}
+	if !cfg.IsEnabled() {
+		return
+	}
+	if err := mng.RegisterCollector(app.NewAppCollector(*cfg, mm.configStats, logrus.StandardLogger())); err != nil {
+		logrus.Warnf("Failed to register app collector: %v", err)
+	}
+}
+
+// setupSampler 按配置启用高频队列采样器
+func (mm *MetricsManager) setupSampler(mng *metrics.ManagerV2) {
+	if !mm.config.Sampler.Enabled {
+		return
+	}
+	s := sampler.NewBacklogSampler(mm.config.Sampler, logrus.StandardLogger())
+	if err := mng.RegisterCollector(s); err != nil {
+		logrus.Warnf("Failed to register qdisc sampler: %v", err)
+		return
+	}
+	logrus.Infof("Qdisc sampler enabled with interval %v", mm.config.Sampler.Interval)
+}
+
+// Stop 停止后台收集任务
+func (mm *MetricsManager) Stop() {
+	if mm.manager != nil {
+		mm.manager.Shutdown()
+	}
+}
+
 // GetRegistry 获取Prometheus注册表
 func (mm *MetricsManager) GetRegistry() *prometheus.Registry {
 	return mm.promReg
 }
 
-// Reload 重新加载指标配置
-func (mm *MetricsManager) Reload() error {
-	logrus.Info("Reloading metrics configuration")
+// GetGatherer 获取完整抓取使用的 Gatherer，并发抓取由 ManagerV2 合并
+func (mm *MetricsManager) GetGatherer() prometheus.Gatherer {
+	return mm.promReg
+}
 
-	// 清理现有注册表
-	mm.promReg = prometheus.NewRegistry()
+// FilteredGatherer 为单次请求创建只包含所选收集器的注册表，供 collect[]/exclude[] 查询参数使用
+// 选择中包含未知或未启用的收集器时返回错误
+func (mm *MetricsManager) FilteredGatherer(include, exclude []string) (prometheus.Gatherer, error) {
+	if mm.manager == nil {
+		return nil, fmt.Errorf("metrics manager not initialized")
+	}
+	collectors, err := mm.manager.SelectCollectors(include, exclude)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(collectors))
+	for i, collector := range collectors {
+		ids[i] = collector.ID()
+	}
+	reg := prometheus.NewRegistry()
+	if err := reg.Register(tc_collector.CollectorFunc(func(ch chan<- prometheus.Metric) {
+		if err := mm.manager.CollectSelected(ch, ids, nil); err != nil {
+			logrus.Warnf("Filtered collection failed: %v", err)
+		}
+	})); err != nil {
+		return nil, err
+	}
+	return reg, nil
+}
+
+// ProbeGatherer 为单次 /probe 请求创建只收集指定命名空间（及可选设备）的注册表
+// 命名空间或设备不存在、收集失败时 tc_exporter_probe_success 为 0
+func (mm *MetricsManager) ProbeGatherer(netns, device string) (prometheus.Gatherer, error) {
+	if mm.manager == nil {
+		return nil, fmt.Errorf("metrics manager not initialized")
+	}
+	reg := prometheus.NewRegistry()
+	if err := reg.Register(tc_collector.CollectorFunc(func(ch chan<- prometheus.Metric) {
+		start := time.Now()
+		success := 1.0
+		if err := mm.manager.CollectScope(ch, netns, device); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"error_code": errors.GetErrorCode(err),
+				"netns":      netns,
+				"device":     device,
+			}).Warnf("Probe failed: %v", err)
+			success = 0
+		}
+		ch <- prometheus.MustNewConstMetric(probeDurationDesc, prometheus.GaugeValue, time.Since(start).Seconds())
+		ch <- prometheus.MustNewConstMetric(probeSuccessDesc, prometheus.GaugeValue, success)
+	})); err != nil {
+		return nil, err
+	}
+	return reg, nil
+}
 
-	// 重新设置
-	mm.Setup()
+// GetManager 获取收集器管理器
+func (mm *MetricsManager) GetManager() *metrics.ManagerV2 {
+	return mm.manager
+}
 
-	logrus.Info("Metrics configuration reloaded successfully")
-	return nil
+// Activate 重新将全局 netlink 观察者指向本管理器，用于热重载回滚
+func (mm *MetricsManager) Activate() {
+	if mm.manager != nil {
+		mm.manager.AttachNetlinkObserver()
+	}
 }
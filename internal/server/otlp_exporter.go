@@ -0,0 +1,187 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"time"
+
+	"gitee.com/openeuler/uos-tc-exporter/internal/exporter"
+	"gitee.com/openeuler/uos-tc-exporter/pkg/errors"
+	"gitee.com/openeuler/uos-tc-exporter/pkg/otlp"
+	"gitee.com/openeuler/uos-tc-exporter/version"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// otlpServiceName service.name 资源属性的默认值
+	otlpServiceName = "uos_tc_exporter"
+	// otlpScopeName 导出指标的 instrumentation scope
+	otlpScopeName = "gitee.com/openeuler/uos-tc-exporter"
+)
+
+// processStart 累计指标（Sum、Histogram、Summary）的起始时间
+// 使用进程启动时间而不是导出器创建时间，热重载重建导出器不会被后端视为计数器重置
+var processStart = time.Now()
+
+// otlpHostArch GOARCH 到 OpenTelemetry host.arch 取值的映射，未列出的原样使用
+var otlpHostArch = map[string]string{
+	"386":     "x86",
+	"arm":     "arm32",
+	"ppc64le": "ppc64",
+}
+
+// OTLPExporter 定期将收集器指标以 OTLP/HTTP（protobuf 编码）导出
+type OTLPExporter struct {
+	cfg exporter.OTLPConfig
+	// gatherer 返回当前生效的 Gatherer，热重载后随之更新
+	gatherer func() prometheus.Gatherer
+	client   *http.Client
+	resource []otlp.KeyValue
+	loop     *senderLoop
+}
+
+// NewOTLPExporter 根据配置创建导出器并确定资源属性，不启动导出
+func NewOTLPExporter(cfg exporter.OTLPConfig, gatherer func() prometheus.Gatherer) (*OTLPExporter, error) {
+	client, err := newSenderClient(cfg.Timeout, cfg.TLS, "OTLP")
+	if err != nil {
+		return nil, err
+	}
+	resource, err := otlpResource(cfg.ResourceAttributes)
+	if err != nil {
+		return nil, err
+	}
+	return &OTLPExporter{
+		cfg:      cfg,
+		gatherer: gatherer,
+		client:   client,
+		resource: resource,
+		loop:     newSenderLoop(cfg.Interval),
+	}, nil
+}
+
+// otlpResource 返回描述本机的资源属性，extra 中的属性覆盖默认值
+func otlpResource(extra map[string]string) ([]otlp.KeyValue, error) {
+	arch := runtime.GOARCH
+	if mapped, ok := otlpHostArch[arch]; ok {
+		arch = mapped
+	}
+	defaults := map[string]string{
+		"service.name":    otlpServiceName,
+		"service.version": version.Version,
+		"host.arch":       arch,
+		"os.type":         runtime.GOOS,
+	}
+	for key, value := range extra {
+		defaults[key] = value
+	}
+	attrs, err := hostLabels(defaults, "OTLP resource", "service.instance.id", "host.name")
+	if err != nil {
+		return nil, err
+	}
+	resource := make([]otlp.KeyValue, 0, len(attrs))
+	for _, key := range sortedLabelNames(attrs) {
+		resource = append(resource, otlp.KeyValue{Key: key, Value: attrs[key]})
+	}
+	return resource, nil
+}
+
+// Start 在后台启动导出，启动后立即导出一次
+func (e *OTLPExporter) Start() {
+	logrus.WithFields(logrus.Fields{
+		"endpoint": e.cfg.Endpoint,
+		"interval": e.cfg.Interval,
+	}).Info("Starting OTLP metrics export")
+	e.loop.start(func(cancel <-chan struct{}) {
+		e.exportWithRetry(cancel)
+	})
+}
+
+// Stop 停止后台导出并等待正在进行的导出结束，final 为 true 时再导出一次最终结果（不重试）
+func (e *OTLPExporter) Stop(final bool) error {
+	e.loop.shutdown()
+	if !final {
+		return nil
+	}
+	if _, err := e.exportOnce(); err != nil {
+		e.logFailure(err, 0)
+		return err
+	}
+	logrus.Info("Final OTLP export completed")
+	return nil
+}
+
+// exportWithRetry 导出一次，可重试的失败按指数退避重试，cancel 关闭时放弃等待
+func (e *OTLPExporter) exportWithRetry(cancel <-chan struct{}) error {
+	err := sendWithRetry(cancel, e.cfg.MaxRetries, newBackoff(e.cfg.RetryBackoff, e.cfg.Interval),
+		e.exportOnce, e.logFailure)
+	if err == nil {
+		logrus.Debugf("Exported metrics to %s", e.cfg.Endpoint)
+	}
+	return err
+}
+
+// exportOnce 收集当前指标并导出一次，返回失败是否可以重试
+func (e *OTLPExporter) exportOnce() (retryable bool, err error) {
+	now := time.Now()
+	families, err := e.gatherer().Gather()
+	if err != nil {
+		// 部分收集器失败时 Gather 仍返回其余指标
+		logrus.Warnf("OTLP gather returned errors: %v", err)
+	}
+	body := otlp.MarshalMetrics(e.resource, otlp.Scope{Name: otlpScopeName, Version: version.Version},
+		families, processStart, now)
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.cfg.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.cfg.Endpoint, nil)
+	if err != nil {
+		return false, err
+	}
+	for name, value := range e.cfg.Headers {
+		req.Header.Set(name, string(value))
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	if e.cfg.Compression == exporter.OTLPCompressionGzip {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		gz.Write(body)
+		gz.Close()
+		body = buf.Bytes()
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		io.Copy(io.Discard, resp.Body)
+		return false, nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("server returned HTTP status %s: %s", resp.Status, bytes.TrimSpace(msg))
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true, err
+	}
+	return false, err
+}
+
+func (e *OTLPExporter) logFailure(err error, attempt int) {
+	logSendFailure(errors.ErrCodeNetwork, err, "OTLP export failed", logrus.Fields{
+		"endpoint": e.cfg.Endpoint,
+		"attempt":  attempt + 1,
+	})
+}
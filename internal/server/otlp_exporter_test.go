@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gitee.com/openeuler/uos-tc-exporter/internal/exporter"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestOTLPExporterExport(t *testing.T) {
+	var (
+		status   []int
+		requests int
+		bodies   []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Content-Type") != "application/x-protobuf" || r.Header.Get("X-Tenant") != "edge" {
+			http.Error(w, "bad headers", http.StatusUnsupportedMediaType)
+			return
+		}
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(gz)
+		bodies = append(bodies, string(body))
+		if len(status) > 0 {
+			code := status[0]
+			status = status[1:]
+			http.Error(w, "failed", code)
+		}
+	}))
+	defer srv.Close()
+
+	reg := prometheus.NewRegistry()
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "tc_test_total", Help: "test"})
+	reg.MustRegister(counter)
+
+	cfg := exporter.DefaultOTLPConfig
+	cfg.Enabled, cfg.Endpoint = true, srv.URL
+	cfg.Headers = map[string]exporter.Secret{"X-Tenant": "edge"}
+	cfg.ResourceAttributes = map[string]string{"deployment.environment": "lab", "host.name": "edge-1"}
+	cfg.RetryBackoff, cfg.Interval = time.Millisecond, time.Hour
+	e, err := NewOTLPExporter(cfg, func() prometheus.Gatherer { return reg })
+	if err != nil {
+		t.Fatalf("NewOTLPExporter() error = %v", err)
+	}
+
+	// 503 可以重试
+	status = []int{http.StatusServiceUnavailable}
+	if err := e.exportWithRetry(nil); err != nil || requests != 2 {
+		t.Fatalf("exportWithRetry() error = %v after %d requests, want success on the second attempt", err, requests)
+	}
+	for _, want := range []string{"tc_test_total", "deployment.environment", "edge-1", otlpServiceName} {
+		if !strings.Contains(bodies[1], want) {
+			t.Errorf("request body does not contain %q", want)
+		}
+	}
+
+	// 400 不重试
+	status, requests = []int{http.StatusBadRequest}, 0
+	if err := e.exportWithRetry(nil); err == nil || requests != 1 {
+		t.Errorf("exportWithRetry() error = %v after %d requests, want a single failed attempt", err, requests)
+	}
+}
@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gitee.com/openeuler/uos-tc-exporter/internal/privilege"
+	"gitee.com/openeuler/uos-tc-exporter/pkg/errors"
+	"github.com/alecthomas/kingpin"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	dropPrivileges   *bool
+	runAsUser        *string
+	runAsGroup       *string
+	keepCapabilities *[]string
+)
+
+// netlinkFreeCollectors 不访问 netlink、不受能力缺失影响的收集器
+var netlinkFreeCollectors = map[string]bool{"app": true}
+
+func init() {
+	dropPrivileges = kingpin.Flag(
+		"drop-privileges",
+		"keep only the capabilities needed for TC collection (CAP_NET_ADMIN, CAP_SYS_ADMIN) and drop all others at startup; "+
+			"CAP_SYS_ADMIN stays effective for the whole run because every scrape enters network namespaces, so this narrows but does not sandbox the process").
+		Bool()
+	runAsUser = kingpin.Flag(
+		"run-as-user",
+		"user to switch to after dropping privileges, the needed capabilities are kept as ambient capabilities; implies --drop-privileges").
+		String()
+	runAsGroup = kingpin.Flag(
+		"run-as-group",
+		"group to switch to, defaults to the primary group of --run-as-user").
+		String()
+	keepCapabilities = kingpin.Flag(
+		"keep-capability",
+		"additional capability to keep when dropping privileges, e.g. CAP_NET_BIND_SERVICE or CAP_CHOWN (repeatable)").
+		Strings()
+}
+
+// applyPrivileges 按命令行参数降权，降权后重新执行当前程序，因此必须在打开监听和收集之前调用
+func (s *Server) applyPrivileges() error {
+	if privilege.Dropped() || (!*dropPrivileges && *runAsUser == "") {
+		return nil
+	}
+	keep, err := privilege.ParseCapabilities(*keepCapabilities)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeConfig, "invalid --keep-capability")
+	}
+	logrus.WithFields(logrus.Fields{
+		"user":         *runAsUser,
+		"group":        *runAsGroup,
+		"capabilities": (privilege.Required() | keep).String(),
+	}).Info("Dropping privileges and re-executing")
+	err = privilege.Drop(privilege.Options{User: *runAsUser, Group: *runAsGroup, Keep: keep})
+	customErr := errors.Wrap(err, errors.ErrCodeSystem, "failed to drop privileges")
+	customErr.WithContext("user", *runAsUser)
+	logrus.WithFields(logrus.Fields{
+		"error_code": customErr.Code,
+		"error":      customErr.Error(),
+		"user":       *runAsUser,
+	}).Error("Dropping privileges failed")
+	return customErr
+}
+
+// checkPrivileges 启动自检：检查收集需要的能力，记录缺少的能力和因此降级的收集器
+// 返回的错误由 privileges 健康检查器报告，缺少能力时为 DegradedError
+func (s *Server) checkPrivileges() error {
+	report, err := privilege.Check()
+	if err != nil {
+		return Degraded(fmt.Errorf("capability self-check failed: %w", err))
+	}
+	logrus.WithFields(logrus.Fields{
+		"uid":          report.UID,
+		"capabilities": report.Effective.String(),
+		"dropped":      privilege.Dropped(),
+	}).Info("Capability self-check")
+	if len(report.Missing) == 0 {
+		return nil
+	}
+
+	collectors := s.netlinkCollectors()
+	missing := make([]string, len(report.Missing))
+	for i, req := range report.Missing {
+		missing[i] = fmt.Sprintf("%s (%s)", privilege.CapabilityName(req.Capability), req.Impact)
+		customErr := errors.New(errors.ErrCodeNetlinkOperation, "missing capability for netlink operations")
+		customErr.WithContext("capability", privilege.CapabilityName(req.Capability)).WithContext("collectors", collectors)
+		logrus.WithFields(logrus.Fields{
+			"error_code": customErr.Code,
+			"capability": privilege.CapabilityName(req.Capability),
+			"impact":     req.Impact,
+			"collectors": strings.Join(collectors, ","),
+		}).Warn("Missing capability, collectors will be degraded")
+	}
+	return Degraded(fmt.Errorf("missing %s; degraded collectors: %s",
+		strings.Join(missing, ", "), strings.Join(collectors, ", ")))
+}
+
+// netlinkCollectors 返回已注册的、依赖 netlink 的收集器 ID
+func (s *Server) netlinkCollectors() []string {
+	var ids []string
+	if s.metricsMgr == nil || s.metricsMgr.GetManager() == nil {
+		return ids
+	}
+	for _, state := range s.metricsMgr.GetManager().CollectorStates() {
+		if !netlinkFreeCollectors[state.ID] {
+			ids = append(ids, state.ID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
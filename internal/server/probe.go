@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"gitee.com/openeuler/uos-tc-exporter/internal/tc"
+)
+
+// serveProbe 处理 /probe?netns=<name>&device=<dev>，只收集单个命名空间（及可选设备）的指标，
+// 不遍历全部命名空间，适用于 Prometheus 多目标（blackbox 风格）抓取
+func (hs *HttpServer) serveProbe(w http.ResponseWriter, r *http.Request) {
+	req := NewRequest(w, r)
+	query := r.URL.Query()
+	netns, device := query.Get("netns"), query.Get("device")
+	if netns == "" {
+		req.Error = fmt.Errorf("netns parameter is missing")
+		req.Fail(http.StatusBadRequest)
+		return
+	}
+	// 命名空间名称会被拼接到 tc.NetNSDir 下，必须先校验以防止路径穿越
+	if err := tc.ValidateNamespaceName(netns); err != nil {
+		req.Error = err
+		req.Fail(http.StatusBadRequest)
+		return
+	}
+
+	release, ok := hs.beginScrape(req)
+	if !ok {
+		return
+	}
+	defer release()
+
+	hs.mu.RLock()
+	metrics := hs.metrics
+	hs.mu.RUnlock()
+	gatherer, err := metrics.ProbeGatherer(netns, device)
+	if err != nil {
+		req.Error = err
+		req.Fail(http.StatusInternalServerError)
+		return
+	}
+	newMetricsHandler(gatherer).ServeHTTP(w, r)
+}
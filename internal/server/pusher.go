@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"gitee.com/openeuler/uos-tc-exporter/internal/exporter"
+	"gitee.com/openeuler/uos-tc-exporter/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+	"github.com/sirupsen/logrus"
+)
+
+// Pusher 定期将收集器指标推送到 Pushgateway，用于位于 NAT 之后、无法被抓取的主机
+type Pusher struct {
+	cfg exporter.PushConfig
+	// gatherer 返回当前生效的 Gatherer，热重载后随之更新
+	gatherer func() prometheus.Gatherer
+	client   *http.Client
+	// grouping 分组标签，未配置 instance 时为主机名
+	grouping map[string]string
+	password string
+	loop     *senderLoop
+}
+
+// NewPusher 根据配置创建推送器，加载 TLS 证书和认证密码，不启动推送
+func NewPusher(cfg exporter.PushConfig, gatherer func() prometheus.Gatherer) (*Pusher, error) {
+	client, err := newSenderClient(cfg.Timeout, cfg.TLS, "push")
+	if err != nil {
+		return nil, err
+	}
+	p := &Pusher{
+		cfg:      cfg,
+		gatherer: gatherer,
+		client:   client,
+		loop:     newSenderLoop(cfg.Interval),
+	}
+	if cfg.BasicAuth != nil {
+		if p.password, err = cfg.BasicAuth.Credentials(); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeConfig, "invalid push basic auth config")
+		}
+	}
+	if p.grouping, err = hostLabels(cfg.Grouping, "push grouping", "instance"); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Start 在后台启动推送，启动后立即推送一次
+func (p *Pusher) Start() {
+	logrus.WithFields(logrus.Fields{
+		"url":      p.cfg.URL,
+		"job":      p.cfg.Job,
+		"interval": p.cfg.Interval,
+	}).Info("Starting Pushgateway push")
+	p.loop.start(func(cancel <-chan struct{}) {
+		p.pushWithRetry(cancel)
+	})
+}
+
+// Stop 停止后台推送并等待正在进行的推送结束，final 为 true 时再推送一次最终结果（不重试）
+func (p *Pusher) Stop(final bool) error {
+	p.loop.shutdown()
+	if !final {
+		return nil
+	}
+	if err := p.pushOnce(); err != nil {
+		p.logFailure(err, 0)
+		return err
+	}
+	logrus.Info("Final push to Pushgateway completed")
+	return nil
+}
+
+// pushWithRetry 推送一次，失败时按指数退避重试，cancel 关闭时放弃等待
+func (p *Pusher) pushWithRetry(cancel <-chan struct{}) error {
+	err := sendWithRetry(cancel, p.cfg.MaxRetries, newBackoff(p.cfg.RetryBackoff, p.cfg.Interval),
+		func() (bool, error) { return true, p.pushOnce() }, p.logFailure)
+	if err == nil {
+		logrus.Debugf("Pushed metrics to %s", p.cfg.URL)
+	}
+	return err
+}
+
+// pushOnce 收集当前指标并推送一次
+func (p *Pusher) pushOnce() error {
+	pusher := push.New(p.cfg.URL, p.cfg.Job).Gatherer(p.gatherer()).Client(p.client)
+	for name, value := range p.grouping {
+		pusher = pusher.Grouping(name, value)
+	}
+	if p.cfg.BasicAuth != nil {
+		pusher = pusher.BasicAuth(p.cfg.BasicAuth.Username, p.password)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.Timeout)
+	defer cancel()
+	if p.cfg.Method == exporter.PushMethodPost {
+		return pusher.AddContext(ctx)
+	}
+	return pusher.PushContext(ctx)
+}
+
+func (p *Pusher) logFailure(err error, attempt int) {
+	logSendFailure(errors.ErrCodeNetwork, err, "Push to Pushgateway failed", logrus.Fields{
+		"url":     p.cfg.URL,
+		"attempt": attempt + 1,
+	})
+}
@@ -0,0 +1,141 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gitee.com/openeuler/uos-tc-exporter/internal/exporter"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// pushgateway 记录收到的推送，前 failures 次返回 500
+type pushgateway struct {
+	mu       sync.Mutex
+	failures int
+	requests []string
+}
+
+func (g *pushgateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	user, pass, _ := r.BasicAuth()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.requests = append(g.requests, r.Method+" "+r.URL.Path+" "+user+":"+pass)
+	if g.failures > 0 {
+		g.failures--
+		http.Error(w, "unavailable", http.StatusInternalServerError)
+		return
+	}
+	if !strings.Contains(string(body), "tc_test_metric") {
+		http.Error(w, "missing metric", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (g *pushgateway) count() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.requests)
+}
+
+func newTestPusher(t *testing.T, url string) *Pusher {
+	t.Helper()
+	reg := prometheus.NewRegistry()
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "tc_test_metric", Help: "test"})
+	gauge.Set(1)
+	reg.MustRegister(gauge)
+
+	cfg := exporter.DefaultPushConfig
+	cfg.Enabled, cfg.URL = true, url
+	cfg.Grouping = map[string]string{"instance": "edge-1", "site": "lab"}
+	cfg.RetryBackoff = time.Millisecond
+	cfg.MaxRetries = 2
+	cfg.Interval = time.Hour
+	cfg.BasicAuth = &exporter.BasicAuthConfig{Username: "push", Password: "secret"}
+	pusher, err := NewPusher(cfg, func() prometheus.Gatherer { return reg })
+	if err != nil {
+		t.Fatalf("NewPusher() error = %v", err)
+	}
+	return pusher
+}
+
+func TestPusherRetry(t *testing.T) {
+	gateway := &pushgateway{failures: 2}
+	srv := httptest.NewServer(gateway)
+	defer srv.Close()
+
+	pusher := newTestPusher(t, srv.URL)
+	if err := pusher.pushWithRetry(nil); err != nil {
+		t.Fatalf("pushWithRetry() error = %v, want success on the third attempt", err)
+	}
+	// push 库内部用 map 保存分组标签，URL 中分组标签的顺序不固定
+	if len(gateway.requests) != 3 {
+		t.Fatalf("requests = %v, want 3 requests", gateway.requests)
+	}
+	last := gateway.requests[2]
+	if !strings.HasPrefix(last, "PUT /metrics/job/tc_exporter/") || !strings.HasSuffix(last, " push:secret") ||
+		!strings.Contains(last, "/instance/edge-1") || !strings.Contains(last, "/site/lab") {
+		t.Errorf("last request = %q, want PUT with job, instance and site grouping and basic auth", last)
+	}
+
+	gateway.failures = 10
+	if err := pusher.pushWithRetry(nil); err == nil {
+		t.Error("pushWithRetry() error = nil, want error after retries are exhausted")
+	}
+}
+
+func TestPusherFinalPush(t *testing.T) {
+	gateway := &pushgateway{}
+	srv := httptest.NewServer(gateway)
+	defer srv.Close()
+
+	pusher := newTestPusher(t, srv.URL)
+	pusher.Start()
+	if err := pusher.Stop(true); err != nil {
+		t.Fatalf("Stop(true) error = %v", err)
+	}
+	// 启动时推送一次，停止时再推送一次
+	if got := gateway.count(); got != 2 {
+		t.Errorf("push count = %d, want 2", got)
+	}
+}
+
+func TestServerStopWithSlowFinalPush(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		http.Error(w, "unavailable", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "tc-exporter.yaml")
+	if err := os.WriteFile(path, []byte("server:\n  shutdownTimeout: 20ms\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	configMgr, err := exporter.NewConfigManager(path)
+	if err != nil {
+		t.Fatalf("NewConfigManager() error = %v", err)
+	}
+	if err := configMgr.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	pusher := newTestPusher(t, srv.URL)
+	s := &Server{configMgr: configMgr, pusher: pusher}
+
+	// 最终推送超过关闭超时，Stop 先返回，推送失败后写入错误通道不能 panic
+	s.Stop()
+	close(release)
+	// 等待后台的最终推送失败并写入错误
+	time.Sleep(100 * time.Millisecond)
+}
@@ -31,6 +31,7 @@ func Ratelimit(ratelimiter *ratelimit.RateLimiter) HandlerFunc {
 	logrus.Debugf("ratelimit middleware init rateLimitInterval: %v, rateLimitSize: %v\n", *rateLimitInterval, *rateLimitSize)
 	return func(req *Request) {
 		if err := ratelimiter.Get(); err != nil {
+			requestsRejected.WithLabelValues(rejectGlobalRateLimit).Inc()
 			req.Error = err
 			req.Fail(429)
 		}
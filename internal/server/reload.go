@@ -0,0 +1,247 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"reflect"
+
+	"gitee.com/openeuler/uos-tc-exporter/internal/exporter"
+	"gitee.com/openeuler/uos-tc-exporter/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// reloadStage 配置热重载的一个阶段
+//
+// changed 判断该阶段关心的配置是否变化；apply 应用新配置，
+// 返回的 rollback 在后续阶段失败时恢复旧状态，commit 在全部阶段成功后释放旧资源。
+type reloadStage struct {
+	name    string
+	changed func(oldCfg, newCfg *exporter.Config) bool
+	apply   func(oldCfg, newCfg *exporter.Config) (rollback, commit func(), err error)
+}
+
+// reloadStages 返回按顺序执行的重载阶段
+// 监听阶段放在最后：它只在绑定新地址成功后才改变状态，之后不会再有失败的阶段
+func (s *Server) reloadStages() []reloadStage {
+	return []reloadStage{
+		{
+			name: "logging",
+			changed: func(oldCfg, newCfg *exporter.Config) bool {
+				return oldCfg.Logging != newCfg.Logging
+			},
+			apply: s.reloadLogging,
+		},
+		{
+			name: "metrics",
+			changed: func(oldCfg, newCfg *exporter.Config) bool {
+				return !reflect.DeepEqual(oldCfg.Monitoring, newCfg.Monitoring) ||
+					!reflect.DeepEqual(oldCfg.Collectors, newCfg.Collectors) ||
+					!reflect.DeepEqual(oldCfg.Sampler, newCfg.Sampler)
+			},
+			apply: s.reloadMetrics,
+		},
+		{
+			name: "push",
+			changed: func(oldCfg, newCfg *exporter.Config) bool {
+				return !reflect.DeepEqual(oldCfg.Push, newCfg.Push)
+			},
+			apply: s.reloadPush,
+		},
+		{
+			name: "remote_write",
+			changed: func(oldCfg, newCfg *exporter.Config) bool {
+				return !reflect.DeepEqual(oldCfg.RemoteWrite, newCfg.RemoteWrite)
+			},
+			apply: s.reloadRemoteWrite,
+		},
+		{
+			name: "otlp",
+			changed: func(oldCfg, newCfg *exporter.Config) bool {
+				return !reflect.DeepEqual(oldCfg.OTLP, newCfg.OTLP)
+			},
+			apply: s.reloadOTLP,
+		},
+		{
+			name: "listener",
+			changed: func(oldCfg, newCfg *exporter.Config) bool {
+				// 配置了 Web 配置文件时每次重载都重新读取，以便应用证书和认证的变化
+				return newCfg.WebConfigFile != "" ||
+					oldCfg.WebConfigFile != newCfg.WebConfigFile ||
+					!reflect.DeepEqual(oldCfg.ListenAddresses(), newCfg.ListenAddresses()) ||
+					oldCfg.ScrapePath() != newCfg.ScrapePath() ||
+					!reflect.DeepEqual(oldCfg.Limits, newCfg.Limits) ||
+					oldCfg.Health != newCfg.Health ||
+					oldCfg.Server != newCfg.Server
+			},
+			apply: s.reloadListener,
+		},
+	}
+}
+
+// applyConfig 作为 ConfigManager 的重载回调，按阶段差量应用新配置
+// 任一阶段失败时按逆序回滚已完成的阶段并返回错误，ConfigManager 随后恢复旧配置
+func (s *Server) applyConfig(newCfg *exporter.Config) error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	oldCfg := s.applied
+	var (
+		rollbacks []func()
+		commits   []func()
+		applied   []string
+	)
+	for _, stage := range s.reloadStages() {
+		if !stage.changed(&oldCfg, newCfg) {
+			continue
+		}
+		rollback, commit, err := stage.apply(&oldCfg, newCfg)
+		if err != nil {
+			for i := len(rollbacks) - 1; i >= 0; i-- {
+				rollbacks[i]()
+			}
+			customErr := errors.Wrap(err, errors.ErrCodeConfig, "config reload failed")
+			customErr.WithContext("stage", stage.name).WithContext("applied_stages", applied)
+			logrus.WithFields(logrus.Fields{
+				"error_code":     customErr.Code,
+				"error":          customErr.Error(),
+				"stage":          stage.name,
+				"applied_stages": applied,
+			}).Error("Config reload failed, rolled back")
+			return customErr
+		}
+		applied = append(applied, stage.name)
+		if rollback != nil {
+			rollbacks = append(rollbacks, rollback)
+		}
+		if commit != nil {
+			commits = append(commits, commit)
+		}
+	}
+
+	for _, commit := range commits {
+		commit()
+	}
+	s.applied = *newCfg
+	if len(applied) == 0 {
+		logrus.Info("Config reloaded, no runtime changes required")
+	} else {
+		logrus.Infof("Config reloaded, applied stages: %v", applied)
+	}
+	return nil
+}
+
+// reloadLogging 重新初始化日志级别和输出文件
+func (s *Server) reloadLogging(oldCfg, newCfg *exporter.Config) (func(), func(), error) {
+	if err := setupLogFrom(*newCfg); err != nil {
+		return nil, nil, err
+	}
+	rollback := func() {
+		if err := setupLogFrom(*oldCfg); err != nil {
+			logrus.Errorf("Failed to roll back logging config: %v", err)
+		}
+	}
+	return rollback, nil, nil
+}
+
+// reloadMetrics 根据新配置重建 ManagerV2 和注册表，并切换 HTTP 指标端点
+// 旧的管理器在所有阶段成功后才停止，回滚时直接切回
+func (s *Server) reloadMetrics(_, newCfg *exporter.Config) (func(), func(), error) {
+	oldMgr := s.metricsMgr
+	newMgr := NewMetricsManager(*newCfg, s.configMgr.GetStats)
+	newMgr.Setup()
+
+	s.metricsMgr = newMgr
+	s.httpServer.SetMetricsSource(newMgr)
+
+	rollback := func() {
+		s.metricsMgr = oldMgr
+		s.httpServer.SetMetricsSource(oldMgr)
+		oldMgr.Activate()
+		newMgr.Stop()
+	}
+	commit := func() {
+		oldMgr.Stop()
+	}
+	return rollback, commit, nil
+}
+
+// reloadPush 按新配置重建推送器，旧推送器在所有阶段成功后停止，新推送器随后启动
+func (s *Server) reloadPush(_, newCfg *exporter.Config) (func(), func(), error) {
+	var newPusher *Pusher
+	if newCfg.Push.Enabled {
+		var err error
+		if newPusher, err = NewPusher(newCfg.Push, s.httpServer.MetricsGatherer); err != nil {
+			return nil, nil, err
+		}
+	}
+	commit := func() {
+		if s.pusher != nil {
+			s.pusher.Stop(false)
+		}
+		s.pusher = newPusher
+		if newPusher != nil {
+			newPusher.Start()
+		}
+	}
+	return nil, commit, nil
+}
+
+// reloadRemoteWrite 按新配置重建 remote_write 发送器
+// 新发送器在旧发送器停止后才打开 WAL，未发送的段由新发送器继续发送
+func (s *Server) reloadRemoteWrite(_, newCfg *exporter.Config) (func(), func(), error) {
+	var newWriter *RemoteWriter
+	if newCfg.RemoteWrite.Enabled {
+		var err error
+		if newWriter, err = NewRemoteWriter(newCfg.RemoteWrite, s.httpServer.MetricsGatherer); err != nil {
+			return nil, nil, err
+		}
+	}
+	commit := func() {
+		if s.remoteWriter != nil {
+			s.remoteWriter.Stop()
+		}
+		s.remoteWriter = newWriter
+		if newWriter == nil {
+			return
+		}
+		if err := newWriter.Start(); err != nil {
+			s.remoteWriter = nil
+			logrus.WithFields(logrus.Fields{
+				"error_code": errors.ErrCodeSystem,
+				"error":      err.Error(),
+				"wal_dir":    newCfg.RemoteWrite.WALDir,
+			}).Error("Failed to start remote_write after reload")
+		}
+	}
+	return nil, commit, nil
+}
+
+// reloadOTLP 按新配置重建 OTLP 导出器，旧导出器在所有阶段成功后停止，新导出器随后启动
+func (s *Server) reloadOTLP(_, newCfg *exporter.Config) (func(), func(), error) {
+	var newExporter *OTLPExporter
+	if newCfg.OTLP.Enabled {
+		var err error
+		if newExporter, err = NewOTLPExporter(newCfg.OTLP, s.httpServer.MetricsGatherer); err != nil {
+			return nil, nil, err
+		}
+	}
+	commit := func() {
+		if s.otlpExporter != nil {
+			s.otlpExporter.Stop(false)
+		}
+		s.otlpExporter = newExporter
+		if newExporter != nil {
+			newExporter.Start()
+		}
+	}
+	return nil, commit, nil
+}
+
+// reloadListener 应用监听地址列表、指标路径、TLS、认证、限流配置和关闭超时的变化
+func (s *Server) reloadListener(_, newCfg *exporter.Config) (func(), func(), error) {
+	if err := s.httpServer.ApplyConfig(*newCfg); err != nil {
+		return nil, nil, err
+	}
+	return nil, nil, nil
+}
@@ -0,0 +1,307 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"gitee.com/openeuler/uos-tc-exporter/internal/exporter"
+	"gitee.com/openeuler/uos-tc-exporter/pkg/errors"
+	"gitee.com/openeuler/uos-tc-exporter/pkg/remotewrite"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// 发送失败和丢弃的原因，作为 remote_write 自身指标的 reason 标签
+const (
+	rwReasonNetwork  = "network"
+	rwReasonServer   = "server_error"
+	rwReasonRejected = "rejected"
+	rwReasonWALFull  = "wal_full"
+	rwReasonCorrupt  = "corrupt"
+)
+
+var (
+	// rwQueueSegments WAL 中待发送的段数，每个段对应一次收集
+	rwQueueSegments = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "tc_exporter_remote_write_queue_segments",
+		Help: "Number of collected batches waiting in the remote_write WAL.",
+	})
+	rwQueueBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "tc_exporter_remote_write_queue_bytes",
+		Help: "Size in bytes of the batches waiting in the remote_write WAL.",
+	})
+	rwSent = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "tc_exporter_remote_write_segments_sent_total",
+		Help: "Total number of batches successfully sent to the remote_write endpoint.",
+	})
+	rwFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "tc_exporter_remote_write_send_failures_total",
+		Help: "Total number of failed remote_write send attempts, by reason.",
+	}, []string{"reason"})
+	rwDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "tc_exporter_remote_write_segments_dropped_total",
+		Help: "Total number of batches dropped without being sent, by reason.",
+	}, []string{"reason"})
+	rwLastSend = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "tc_exporter_remote_write_last_send_timestamp_seconds",
+		Help: "Unix timestamp of the last successful remote_write send.",
+	})
+)
+
+func init() {
+	httpRegistry.MustRegister(rwQueueSegments, rwQueueBytes, rwSent, rwFailures, rwDropped, rwLastSend)
+	for _, reason := range []string{rwReasonNetwork, rwReasonServer, rwReasonRejected} {
+		rwFailures.WithLabelValues(reason)
+	}
+	for _, reason := range []string{rwReasonWALFull, rwReasonRejected, rwReasonCorrupt} {
+		rwDropped.WithLabelValues(reason)
+	}
+}
+
+// RemoteWriter 定期收集指标写入磁盘 WAL，并按写入顺序以 remote_write 协议发送
+//
+// 收集和发送在两个协程中进行：端点不可用时收集照常进行，样本留在 WAL 中，
+// 恢复后从最旧的段开始补发。停止时未发送的段保留在磁盘上，下次启动后继续发送。
+type RemoteWriter struct {
+	cfg exporter.RemoteWriteConfig
+	// gatherer 返回当前生效的 Gatherer，热重载后随之更新
+	gatherer func() prometheus.Gatherer
+	client   *http.Client
+	external []remotewrite.Label
+	password string
+	maxBytes int64
+
+	wal  *remotewrite.WAL
+	wake chan struct{}
+	// loop 定时收集，sendLoop 随之停止
+	loop *senderLoop
+	sent chan struct{}
+}
+
+// NewRemoteWriter 根据配置创建发送器，加载 TLS 证书和认证密码，不打开 WAL
+// WAL 在 Start 时打开，热重载时新旧发送器不会同时使用同一个 WAL 目录
+func NewRemoteWriter(cfg exporter.RemoteWriteConfig, gatherer func() prometheus.Gatherer) (*RemoteWriter, error) {
+	client, err := newSenderClient(cfg.Timeout, cfg.TLS, "remote_write")
+	if err != nil {
+		return nil, err
+	}
+	maxBytes, err := cfg.WALMaxBytes()
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeConfig, "invalid remote_write WAL size")
+	}
+	rw := &RemoteWriter{
+		cfg:      cfg,
+		gatherer: gatherer,
+		client:   client,
+		maxBytes: maxBytes,
+		wake:     make(chan struct{}, 1),
+		loop:     newSenderLoop(cfg.Interval),
+		sent:     make(chan struct{}),
+	}
+	if cfg.BasicAuth != nil {
+		if rw.password, err = cfg.BasicAuth.Credentials(); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeConfig, "invalid remote_write basic auth config")
+		}
+	}
+
+	external, err := hostLabels(cfg.ExternalLabels, "remote_write labels", "instance")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range sortedLabelNames(external) {
+		rw.external = append(rw.external, remotewrite.Label{Name: name, Value: external[name]})
+	}
+	return rw, nil
+}
+
+// Start 打开 WAL 并在后台启动收集和发送，WAL 中已有的段会先发送
+func (rw *RemoteWriter) Start() error {
+	wal, err := remotewrite.OpenWAL(rw.cfg.WALDir, rw.maxBytes)
+	if err != nil {
+		customErr := errors.Wrap(err, errors.ErrCodeSystem, "failed to open remote_write WAL")
+		customErr.WithContext("wal_dir", rw.cfg.WALDir)
+		return customErr
+	}
+	rw.wal = wal
+	rw.updateQueueMetrics()
+	logrus.WithFields(logrus.Fields{
+		"url":      rw.cfg.URL,
+		"interval": rw.cfg.Interval,
+		"wal_dir":  rw.cfg.WALDir,
+		"pending":  wal.Len(),
+	}).Info("Starting remote_write")
+
+	go rw.sendLoop()
+	rw.loop.start(func(<-chan struct{}) {
+		rw.collect()
+	})
+	return nil
+}
+
+// Stop 停止收集和发送并等待正在进行的发送结束，未发送的段保留在 WAL 中
+func (rw *RemoteWriter) Stop() {
+	rw.loop.shutdown()
+	if rw.loop.started.Load() {
+		<-rw.sent
+		logrus.WithField("pending", rw.wal.Len()).Info("remote_write stopped")
+	}
+}
+
+// collect 收集一次指标，编码后写入 WAL 并唤醒发送协程
+func (rw *RemoteWriter) collect() {
+	now := time.Now()
+	families, err := rw.gatherer().Gather()
+	if err != nil {
+		// 部分收集器失败时 Gather 仍返回其余指标
+		logrus.Warnf("remote_write gather returned errors: %v", err)
+	}
+	series := remotewrite.FromMetricFamilies(families, now.UnixMilli(), rw.external)
+	if len(series) == 0 {
+		return
+	}
+	dropped, err := rw.wal.Append(remotewrite.EncodeWriteRequest(series))
+	if err != nil {
+		customErr := errors.Wrap(err, errors.ErrCodeSystem, "failed to append to remote_write WAL")
+		customErr.WithContext("wal_dir", rw.cfg.WALDir)
+		logrus.WithFields(logrus.Fields{
+			"error_code": customErr.Code,
+			"error":      customErr.Error(),
+			"wal_dir":    rw.cfg.WALDir,
+		}).Error("Writing remote_write WAL failed")
+	}
+	if dropped > 0 {
+		rwDropped.WithLabelValues(rwReasonWALFull).Add(float64(dropped))
+		logrus.WithFields(logrus.Fields{
+			"dropped":      dropped,
+			"wal_max_size": rw.cfg.WALMaxSize,
+		}).Warn("remote_write WAL full, oldest batches dropped")
+	}
+	rw.updateQueueMetrics()
+	select {
+	case rw.wake <- struct{}{}:
+	default:
+	}
+}
+
+// sendLoop 按写入顺序发送 WAL 中的段，失败时按指数退避重试同一个段，不跳过
+func (rw *RemoteWriter) sendLoop() {
+	defer close(rw.sent)
+	backoff := newBackoff(rw.cfg.RetryBackoff, rw.cfg.Interval)
+	for {
+		sent, err := rw.sendOldest()
+		if sent && err == nil {
+			backoff.Reset()
+			select {
+			case <-rw.loop.stop:
+				return
+			default:
+			}
+			continue
+		}
+		// WAL 为空时等待下一次收集，发送失败时等待退避时间后重试
+		wake, retry := rw.wake, (<-chan time.Time)(nil)
+		if err != nil {
+			wake, retry = nil, time.After(backoff.Next())
+		}
+		select {
+		case <-rw.loop.stop:
+			return
+		case <-wake:
+		case <-retry:
+		}
+	}
+}
+
+// sendOldest 发送最旧的段，返回是否处理了一个段；可重试的失败返回错误，段保留在 WAL 中
+func (rw *RemoteWriter) sendOldest() (bool, error) {
+	seq, data, ok, err := rw.wal.Oldest()
+	if !ok {
+		return false, nil
+	}
+	if err != nil {
+		rwDropped.WithLabelValues(rwReasonCorrupt).Inc()
+		rw.logFailure(errors.ErrCodeSystem, err, seq, "Unreadable remote_write WAL segment dropped")
+		return true, rw.remove(seq)
+	}
+
+	retryable, err := rw.send(data)
+	switch {
+	case err == nil:
+		rwSent.Inc()
+		rwLastSend.SetToCurrentTime()
+		logrus.Debugf("Sent remote_write batch %d to %s", seq, rw.cfg.URL)
+	case retryable:
+		rw.logFailure(errors.ErrCodeNetwork, err, seq, "remote_write send failed, will retry")
+		return false, err
+	default:
+		// 接收端拒绝的数据重试也不会成功，丢弃后继续发送后面的段
+		rwDropped.WithLabelValues(rwReasonRejected).Inc()
+		rw.logFailure(errors.ErrCodeNetwork, err, seq, "remote_write batch rejected, dropped")
+	}
+	return true, rw.remove(seq)
+}
+
+func (rw *RemoteWriter) remove(seq uint64) error {
+	err := rw.wal.Remove(seq)
+	if err != nil {
+		rw.logFailure(errors.ErrCodeSystem, err, seq, "Failed to remove remote_write WAL segment")
+	}
+	rw.updateQueueMetrics()
+	return err
+}
+
+// send 发送一个请求体，返回失败是否可以重试：网络错误、5xx 和 429 可以重试，其余 4xx 不重试
+func (rw *RemoteWriter) send(body []byte) (retryable bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), rw.cfg.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rw.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("User-Agent", "uos-tc-exporter")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	if rw.cfg.BasicAuth != nil {
+		req.SetBasicAuth(rw.cfg.BasicAuth.Username, rw.password)
+	}
+
+	resp, err := rw.client.Do(req)
+	if err != nil {
+		rwFailures.WithLabelValues(rwReasonNetwork).Inc()
+		return true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		io.Copy(io.Discard, resp.Body)
+		return false, nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("server returned HTTP status %s: %s", resp.Status, bytes.TrimSpace(msg))
+	if resp.StatusCode/100 == 5 || resp.StatusCode == http.StatusTooManyRequests {
+		rwFailures.WithLabelValues(rwReasonServer).Inc()
+		return true, err
+	}
+	rwFailures.WithLabelValues(rwReasonRejected).Inc()
+	return false, err
+}
+
+func (rw *RemoteWriter) updateQueueMetrics() {
+	rwQueueSegments.Set(float64(rw.wal.Len()))
+	rwQueueBytes.Set(float64(rw.wal.Size()))
+}
+
+func (rw *RemoteWriter) logFailure(code errors.ErrorCode, err error, seq uint64, msg string) {
+	logSendFailure(code, err, msg, logrus.Fields{
+		"url":     rw.cfg.URL,
+		"segment": seq,
+		"pending": rw.wal.Len(),
+	})
+}
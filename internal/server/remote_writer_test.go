@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"gitee.com/openeuler/uos-tc-exporter/internal/exporter"
+	"gitee.com/openeuler/uos-tc-exporter/pkg/remotewrite"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// remoteWriteReceiver 记录收到的请求体，status 非空时依次返回其中的状态码
+type remoteWriteReceiver struct {
+	mu     sync.Mutex
+	status []int
+	bodies [][]byte
+	header http.Header
+}
+
+func (r *remoteWriteReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.header = req.Header.Clone()
+	if len(r.status) > 0 {
+		status := r.status[0]
+		r.status = r.status[1:]
+		if status != http.StatusNoContent {
+			http.Error(w, "unavailable", status)
+			return
+		}
+	}
+	r.bodies = append(r.bodies, body)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestRemoteWriterOutage(t *testing.T) {
+	receiver := &remoteWriteReceiver{status: []int{
+		http.StatusServiceUnavailable, http.StatusServiceUnavailable, // 端点不可用
+		http.StatusNoContent, http.StatusBadRequest, // 第二个段被拒绝，丢弃后继续
+	}}
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	reg := prometheus.NewRegistry()
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "tc_test_metric", Help: "test"})
+	reg.MustRegister(gauge)
+
+	cfg := exporter.DefaultRemoteWriteConfig
+	cfg.Enabled, cfg.URL = true, srv.URL
+	cfg.WALDir = t.TempDir()
+	cfg.ExternalLabels = map[string]string{"instance": "edge-1"}
+	rw, err := NewRemoteWriter(cfg, func() prometheus.Gatherer { return reg })
+	if err != nil {
+		t.Fatalf("NewRemoteWriter() error = %v", err)
+	}
+	if rw.wal, err = remotewrite.OpenWAL(cfg.WALDir, 1<<20); err != nil {
+		t.Fatal(err)
+	}
+
+	// 端点不可用期间的收集都保留在 WAL 中
+	for i := 1; i <= 4; i++ {
+		gauge.Set(float64(i))
+		rw.collect()
+		if sent, err := rw.sendOldest(); sent || err == nil {
+			if i <= 2 {
+				t.Fatalf("sendOldest() = %v, %v during outage, want retryable error", sent, err)
+			}
+		}
+	}
+	var segments [][]byte
+	files, _ := filepath.Glob(filepath.Join(cfg.WALDir, "*.seg"))
+	for _, file := range files {
+		data, _ := os.ReadFile(file)
+		segments = append(segments, data)
+	}
+	// 第三次收集时恢复，第一个段发送成功；第四次收集时第二个段被拒绝
+	if len(segments) != 2 {
+		t.Fatalf("WAL has %d segments, want 2", len(segments))
+	}
+
+	for {
+		sent, err := rw.sendOldest()
+		if err != nil {
+			t.Fatalf("sendOldest() error = %v", err)
+		}
+		if !sent {
+			break
+		}
+	}
+	if len(receiver.bodies) != 3 || !bytes.Equal(receiver.bodies[1], segments[0]) || !bytes.Equal(receiver.bodies[2], segments[1]) {
+		t.Fatalf("received %d bodies, want 3 with the remaining segments in order", len(receiver.bodies))
+	}
+	if rw.wal.Len() != 0 {
+		t.Errorf("WAL has %d segments after draining, want 0", rw.wal.Len())
+	}
+	for name, want := range map[string]string{
+		"Content-Encoding":                  "snappy",
+		"Content-Type":                      "application/x-protobuf",
+		"X-Prometheus-Remote-Write-Version": "0.1.0",
+	} {
+		if got := receiver.header.Get(name); got != want {
+			t.Errorf("header %s = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -28,3 +28,17 @@ func (r *Request) Fail(status int) {
 	r.ResponseWriter.WriteHeader(status)
 	r.ResponseWriter.Write([]byte(r.Error.Error()))
 }
+
+// Chain 依次执行中间件，任一中间件设置了 Error 时不再调用 handler
+func Chain(handler http.Handler, middlewares ...HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := NewRequest(w, r)
+		for _, middleware := range middlewares {
+			middleware(req)
+			if req.Error != nil {
+				return
+			}
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
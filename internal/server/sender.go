@@ -0,0 +1,171 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gitee.com/openeuler/uos-tc-exporter/internal/exporter"
+	"gitee.com/openeuler/uos-tc-exporter/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Pushgateway 推送、remote_write 和 OTLP 导出共用的发送辅助：HTTP 客户端、主机名默认标签、
+// 后台定时循环、指数退避重试和失败日志
+
+// newSenderClient 创建发送用的 HTTP 客户端，component 用于错误信息
+func newSenderClient(timeout time.Duration, tlsCfg *exporter.ClientTLSConfig, component string) (*http.Client, error) {
+	tlsConfig, err := tlsCfg.Build()
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeConfig, "invalid "+component+" TLS config")
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+	}, nil
+}
+
+// hostLabels 复制 labels，keys 中未设置的标签以主机名为默认值
+func hostLabels(labels map[string]string, component string, keys ...string) (map[string]string, error) {
+	merged := make(map[string]string, len(labels)+len(keys))
+	for _, key := range keys {
+		if _, ok := labels[key]; ok {
+			continue
+		}
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeSystem, "failed to get hostname for "+component)
+		}
+		merged[key] = hostname
+	}
+	for name, value := range labels {
+		merged[name] = value
+	}
+	return merged, nil
+}
+
+// sortedLabelNames 返回按名称排序的标签名，用于生成顺序确定的标签列表
+func sortedLabelNames(labels map[string]string) []string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// senderLoop 后台定时发送：启动后立即运行一次，之后按间隔运行，停止时等待进行中的一次结束
+type senderLoop struct {
+	interval time.Duration
+	// stop 关闭时停止循环，也用于取消重试等待
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+	started  atomic.Bool
+}
+
+func newSenderLoop(interval time.Duration) *senderLoop {
+	return &senderLoop{
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// start 在后台按间隔调用 run，run 的参数在停止时关闭
+func (l *senderLoop) start(run func(cancel <-chan struct{})) {
+	l.started.Store(true)
+	go func() {
+		defer close(l.done)
+		ticker := time.NewTicker(l.interval)
+		defer ticker.Stop()
+		for {
+			run(l.stop)
+			select {
+			case <-l.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// shutdown 停止循环并等待进行中的运行结束，可重复调用，未启动时直接返回
+func (l *senderLoop) shutdown() {
+	l.stopOnce.Do(func() {
+		close(l.stop)
+	})
+	if l.started.Load() {
+		<-l.done
+	}
+}
+
+// backoff 从 initial 开始翻倍、不超过 max 的重试等待时间
+type backoff struct {
+	initial time.Duration
+	max     time.Duration
+	next    time.Duration
+}
+
+func newBackoff(initial, max time.Duration) *backoff {
+	return &backoff{initial: initial, max: max, next: initial}
+}
+
+// Next 返回本次等待时间并将下次等待时间翻倍
+func (b *backoff) Next() time.Duration {
+	d := b.next
+	if b.next *= 2; b.next > b.max {
+		b.next = b.max
+	}
+	return d
+}
+
+// Reset 发送成功后恢复初始等待时间
+func (b *backoff) Reset() {
+	b.next = b.initial
+}
+
+// sendWithRetry 调用 send，可重试的失败按 b 退避最多重试 maxRetries 次，cancel 关闭时放弃等待
+// 每次失败都交给 onFailure 记录，attempt 从 0 开始
+func sendWithRetry(cancel <-chan struct{}, maxRetries int, b *backoff,
+	send func() (retryable bool, err error), onFailure func(err error, attempt int)) error {
+	for attempt := 0; ; attempt++ {
+		retryable, err := send()
+		if err == nil {
+			return nil
+		}
+		onFailure(err, attempt)
+		if !retryable || attempt >= maxRetries {
+			return err
+		}
+		timer := time.NewTimer(b.Next())
+		select {
+		case <-cancel:
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// logSendFailure 以 code 记录一次发送失败，fields 同时作为错误上下文和日志字段
+func logSendFailure(code errors.ErrorCode, err error, msg string, fields logrus.Fields) {
+	customErr := errors.Wrap(err, code, msg)
+	entry := logrus.Fields{}
+	for key, value := range fields {
+		customErr.WithContext(key, value)
+		entry[key] = value
+	}
+	entry["error_code"] = customErr.Code
+	entry["error"] = customErr.Error()
+	logrus.WithFields(entry).Warn(msg)
+}
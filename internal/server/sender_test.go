@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSendWithRetry(t *testing.T) {
+	b := newBackoff(time.Millisecond, 3*time.Millisecond)
+	var attempts []int
+	calls := 0
+	err := sendWithRetry(make(chan struct{}), 3, b, func() (bool, error) {
+		calls++
+		return true, errors.New("unavailable")
+	}, func(err error, attempt int) {
+		attempts = append(attempts, attempt)
+	})
+	if err == nil || calls != 4 || len(attempts) != 4 || attempts[3] != 3 {
+		t.Errorf("sendWithRetry() error = %v, calls = %d, logged attempts = %v, want 4 failed attempts", err, calls, attempts)
+	}
+	// 1ms、2ms 后封顶为 3ms
+	if next := b.Next(); next != 3*time.Millisecond {
+		t.Errorf("backoff after 3 retries = %v, want the 3ms cap", next)
+	}
+
+	// 不可重试的失败和 cancel 关闭时立即返回
+	calls = 0
+	cancel := make(chan struct{})
+	close(cancel)
+	for _, retryable := range []bool{false, true} {
+		sendWithRetry(cancel, 3, newBackoff(time.Hour, time.Hour), func() (bool, error) {
+			calls++
+			return retryable, errors.New("failed")
+		}, func(error, int) {})
+	}
+	if calls != 2 {
+		t.Errorf("send called %d times, want one call each without retrying", calls)
+	}
+}
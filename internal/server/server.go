@@ -11,17 +11,26 @@ import (
 	"gitee.com/openeuler/uos-tc-exporter/internal/exporter"
 	"gitee.com/openeuler/uos-tc-exporter/pkg/logger"
 	"gitee.com/openeuler/uos-tc-exporter/pkg/utils"
+	"gitee.com/openeuler/uos-tc-exporter/version"
 	"github.com/alecthomas/kingpin"
 	"github.com/dustin/go-humanize"
 	"github.com/sirupsen/logrus"
 )
 
+// 子命令，未指定时运行导出器
+const (
+	ServeCommand = "serve"
+	DumpCommand  = "dump"
+	TopCommand   = "top"
+)
+
 var (
 	defaultSeverVersion  = "1.0.0"
 	enableDefaultPromReg *bool
 )
 
 func init() {
+	kingpin.Command(ServeCommand, "Run the exporter (default)").Default()
 	enableDefaultPromReg = kingpin.Flag(
 		"enable-default-prom-reg",
 		"enable default prom reg").
@@ -37,6 +46,18 @@ type Server struct {
 	ExitSignal chan struct{}
 	Error      error
 	callback   sync.Once
+	// reloadMu 串行化热重载，并保护 applied 和 metricsMgr
+	reloadMu sync.Mutex
+	// applied 当前已生效的配置，热重载时与新配置比较
+	applied exporter.Config
+	// pusher 推送到 Pushgateway，未启用时为 nil，由 reloadMu 保护
+	pusher *Pusher
+	// remoteWriter 以 remote_write 协议发送，未启用时为 nil，由 reloadMu 保护
+	remoteWriter *RemoteWriter
+	// otlpExporter 以 OTLP/HTTP 导出，未启用时为 nil，由 reloadMu 保护
+	otlpExporter *OTLPExporter
+	// textfile --output=textfile 时写入 .prom 文件，此时不创建 HTTP 服务器
+	textfile *TextfileWriter
 }
 
 func NewServer(name, version string) *Server {
@@ -59,15 +80,14 @@ func (s *Server) SetUp() error {
 		}
 	}()
 
-	// 解析命令行参数
-	err := s.parse()
-	if err != nil {
-		logrus.Errorf("Parsing command line arguments failed: %v", err)
+	// 按命令行参数降权，成功时重新执行当前程序；在创建日志文件之前执行，使日志文件属于降权后的用户
+	if err := s.applyPrivileges(); err != nil {
 		return err
 	}
 
 	// 初始化配置管理器
 	// s.configMgr = NewConfigManager()
+	var err error
 	s.configMgr, err = exporter.NewConfigManager(*exporter.Configfile)
 	if err != nil {
 		return err
@@ -88,17 +108,71 @@ func (s *Server) SetUp() error {
 
 	// 初始化指标管理器
 	logrus.Info("setup prom")
-	s.metricsMgr = NewMetricsManager()
+	s.metricsMgr = NewMetricsManager(s.configMgr.GetConfig(), s.configMgr.GetStats)
 	s.metricsMgr.Setup()
 
+	// textfile 模式只收集并写入文件，不启动 HTTP 服务器、推送和配置热重载
+	if *outputMode == outputTextfile {
+		s.textfile, err = NewTextfileWriter(*textfileDir, *textfileName, *textfileInterval, s.metricsMgr.GetManager())
+		return err
+	}
+
 	// 初始化HTTP服务器
-	s.httpServer = NewHttpServer(s.configMgr.GetConfig(), s.configMgr.GetConfig().MetricsPath, s.metricsMgr.GetRegistry())
+	cfg := s.configMgr.GetConfig()
+	s.httpServer = NewHttpServer(cfg, cfg.ScrapePath(), s.metricsMgr)
 	err = s.httpServer.Setup(s.metricsMgr)
 	if err != nil {
 		logrus.Errorf("SetUp error: %v", err)
 		return err
 	}
 
+	s.httpServer.SetCollectorPersister(s.configMgr.PersistCollectorSettings)
+
+	// 能力自检，缺少能力时 /health 为 degraded
+	privilegesErr := s.checkPrivileges()
+	s.httpServer.RegisterHealthChecker(NewBasicHealthChecker("privileges", func() error {
+		return privilegesErr
+	}), false)
+
+	// 预先收集一次，首次成功收集后 /ready 返回就绪
+	go s.metricsMgr.WarmUp()
+
+	// 启动 Pushgateway 推送
+	if cfg := s.configMgr.GetConfig(); cfg.Push.Enabled {
+		pusher, err := NewPusher(cfg.Push, s.httpServer.MetricsGatherer)
+		if err != nil {
+			return err
+		}
+		pusher.Start()
+		s.pusher = pusher
+	}
+
+	// 启动 remote_write 发送
+	if cfg := s.configMgr.GetConfig(); cfg.RemoteWrite.Enabled {
+		remoteWriter, err := NewRemoteWriter(cfg.RemoteWrite, s.httpServer.MetricsGatherer)
+		if err != nil {
+			return err
+		}
+		if err := remoteWriter.Start(); err != nil {
+			return err
+		}
+		s.remoteWriter = remoteWriter
+	}
+
+	// 启动 OTLP 导出
+	if cfg := s.configMgr.GetConfig(); cfg.OTLP.Enabled {
+		otlpExporter, err := NewOTLPExporter(cfg.OTLP, s.httpServer.MetricsGatherer)
+		if err != nil {
+			return err
+		}
+		otlpExporter.Start()
+		s.otlpExporter = otlpExporter
+	}
+
+	// 注册热重载回调
+	s.applied = s.configMgr.GetConfig()
+	s.configMgr.SetReloadCallback(s.applyConfig)
+
 	// 启动配置监控
 	if err := s.configMgr.StartWatching(context.TODO()); err != nil {
 		logrus.Warnf("Failed to start config watching: %v, config hot reload will be disabled", err)
@@ -110,7 +184,11 @@ func (s *Server) SetUp() error {
 }
 
 func (s *Server) setupLog() error {
-	config := s.configMgr.GetConfig()
+	return setupLogFrom(s.configMgr.GetConfig())
+}
+
+// setupLogFrom 按给定配置初始化日志，热重载时不能再访问 ConfigManager
+func setupLogFrom(config exporter.Config) error {
 	size, err := humanize.ParseBytes(config.Logging.MaxSize)
 	if err != nil {
 		logrus.Errorf("Parsing log size failed: %v", err)
@@ -126,7 +204,19 @@ func (s *Server) setupLog() error {
 // 这些方法已移至 HttpServer 结构体
 
 func (s *Server) Run() error {
-	go utils.HandleSignals(s.Exit)
+	if s.textfile != nil {
+		go utils.HandleSignalsWith(utils.SignalHandlers{
+			Exit: s.Exit,
+			Dump: s.dumpDiagnostics,
+		})
+		return s.textfile.Run()
+	}
+
+	go utils.HandleSignalsWith(utils.SignalHandlers{
+		Exit:   s.Exit,
+		Reload: s.reloadFromSignal,
+		Dump:   s.dumpDiagnostics,
+	})
 	logrus.Infof("%s successfully setup. SetUp running.", s.Name)
 
 	logrus.Infof("Running %s", s.Name)
@@ -134,7 +224,14 @@ func (s *Server) Run() error {
 }
 
 func (s *Server) PrintVersion() {
-	logrus.Printf("%s version: %s\n", s.Name, s.Version)
+	info := version.Info()
+	logrus.WithFields(logrus.Fields{
+		"version":    s.Version,
+		"revision":   info["revision"],
+		"branch":     info["branch"],
+		"go_version": info["goversion"],
+		"build_time": info["build_time"],
+	}).Infof("%s version: %s", s.Name, s.Version)
 }
 
 func (s *Server) Stop() {
@@ -155,7 +252,16 @@ func (s *Server) Stop() {
 
 	// 使用WaitGroup来协调各个组件的关闭
 	var wg sync.WaitGroup
-	errors := make(chan error, 2) // 最多2个错误（配置监控和HTTP服务器）
+	errors := make(chan error, 4) // 最多4个错误（配置监控、HTTP服务器、推送和 OTLP 导出），每个组件最多写入一次，不会阻塞
+
+	// 停止写入 textfile
+	if s.textfile != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.textfile.Stop()
+		}()
+	}
 
 	// 停止配置监控
 	if s.configMgr != nil {
@@ -171,6 +277,36 @@ func (s *Server) Stop() {
 		}()
 	}
 
+	// 停止推送和导出，收集器停止前再推送、导出一次最终结果，remote_write 未发送的数据保留在 WAL 中
+	s.reloadMu.Lock()
+	pusher, remoteWriter, otlpExporter := s.pusher, s.remoteWriter, s.otlpExporter
+	s.reloadMu.Unlock()
+	if otlpExporter != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := otlpExporter.Stop(true); err != nil {
+				errors <- err
+			}
+		}()
+	}
+	if remoteWriter != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			remoteWriter.Stop()
+		}()
+	}
+	if pusher != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := pusher.Stop(true); err != nil {
+				errors <- err
+			}
+		}()
+	}
+
 	// 停止HTTP服务器
 	if s.httpServer != nil {
 		wg.Add(1)
@@ -202,12 +338,23 @@ func (s *Server) Stop() {
 		}
 	}
 
-	// 检查是否有错误发生
-	close(errors)
+	// HTTP 服务器停止（或超时）后再停止后台收集任务，超时时也要停止高频采样器
+	s.reloadMu.Lock()
+	if s.metricsMgr != nil {
+		s.metricsMgr.Stop()
+	}
+	s.reloadMu.Unlock()
+
+	// 检查是否有错误发生；超时后仍在运行的组件可能稍后写入，因此不关闭通道，只取出已有的错误
 	var errorCount int
-	for err := range errors {
-		if err != nil {
-			errorCount++
+	for drained := false; !drained; {
+		select {
+		case err := <-errors:
+			if err != nil {
+				errorCount++
+			}
+		default:
+			drained = true
 		}
 	}
 
@@ -224,9 +371,10 @@ func (s *Server) Exit() {
 	})
 }
 
-func (s *Server) parse() error {
-	kingpin.Parse()
-	return nil
+// ParseCommandLine 解析命令行参数并返回子命令，参数错误时 kingpin 输出用法后退出
+func ParseCommandLine(name string) string {
+	kingpin.Version(version.Print(name))
+	return kingpin.Parse()
 }
 
 // 这些方法已移至 ConfigManager 结构体
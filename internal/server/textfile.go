@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tc_collector "gitee.com/openeuler/uos-tc-exporter/internal/collectors"
+	"gitee.com/openeuler/uos-tc-exporter/internal/metrics"
+	"gitee.com/openeuler/uos-tc-exporter/pkg/errors"
+	"github.com/alecthomas/kingpin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// 指标输出方式
+const (
+	// outputHTTP 通过 HTTP 指标端点提供指标
+	outputHTTP = "http"
+	// outputTextfile 写入 node_exporter textfile 收集器读取的 .prom 文件
+	outputTextfile = "textfile"
+)
+
+var (
+	outputMode       *string
+	textfileDir      *string
+	textfileName     *string
+	textfileInterval *time.Duration
+)
+
+func init() {
+	outputMode = kingpin.Flag(
+		"output",
+		"how metrics are exposed: http serves the metrics endpoint, textfile writes a .prom file for the node_exporter textfile collector without starting the HTTP server").
+		Default(outputHTTP).
+		Enum(outputHTTP, outputTextfile)
+	textfileDir = kingpin.Flag(
+		"textfile-dir",
+		"directory watched by the node_exporter textfile collector, required with --output=textfile").
+		String()
+	textfileName = kingpin.Flag(
+		"textfile-name",
+		"name of the metrics file written in --textfile-dir, must end with .prom").
+		Default("tc_exporter.prom").
+		String()
+	textfileInterval = kingpin.Flag(
+		"textfile-interval",
+		"interval between writes with --output=textfile, 0 writes the file once and exits").
+		Default("0s").
+		Duration()
+}
+
+// TextfileWriter 定期收集全部 TC 收集器并写入 .prom 文件，供 node_exporter textfile 收集器读取
+// 文件先写入同目录的临时文件再重命名，node_exporter 不会读到写了一半的文件
+type TextfileWriter struct {
+	path     string
+	interval time.Duration
+	gatherer prometheus.Gatherer
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+	started  atomic.Bool
+}
+
+// NewTextfileWriter 创建写入 dir/name 的 TextfileWriter，interval 为 0 时只写入一次
+func NewTextfileWriter(dir, name string, interval time.Duration, manager *metrics.ManagerV2) (*TextfileWriter, error) {
+	if dir == "" {
+		return nil, errors.New(errors.ErrCodeConfig, "--textfile-dir is required with --output=textfile")
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		customErr := errors.New(errors.ErrCodeConfig, "textfile directory does not exist")
+		customErr.WithContext("textfile_dir", dir)
+		return nil, customErr
+	}
+	if !strings.HasSuffix(name, ".prom") || filepath.Base(name) != name {
+		customErr := errors.New(errors.ErrCodeConfig, "textfile name must be a file name ending with .prom")
+		customErr.WithContext("textfile_name", name)
+		return nil, customErr
+	}
+	if interval < 0 {
+		return nil, errors.New(errors.ErrCodeConfig, "--textfile-interval cannot be negative")
+	}
+
+	// 只注册 TC 收集器，输出与 /metrics 中收集器部分相同的指标，不包含导出器自身的 HTTP 和健康指标
+	reg := prometheus.NewRegistry()
+	if err := reg.Register(tc_collector.CollectorFunc(manager.CollectAll)); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeMetrics, "failed to register collectors for textfile output")
+	}
+	return &TextfileWriter{
+		path:     filepath.Join(dir, name),
+		interval: interval,
+		gatherer: reg,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}, nil
+}
+
+// Run 写入文件，interval 为 0 时写入一次后返回，否则按间隔循环写入直到 Stop
+// 循环模式下单次写入失败只记录日志，保留上一次写入的文件
+func (w *TextfileWriter) Run() error {
+	w.started.Store(true)
+	defer close(w.done)
+	logrus.WithFields(logrus.Fields{
+		"path":     w.path,
+		"interval": w.interval,
+	}).Info("Writing metrics to textfile")
+
+	if w.interval == 0 {
+		return w.WriteOnce()
+	}
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		w.WriteOnce()
+		select {
+		case <-w.stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// WriteOnce 收集一次并原子地替换文件
+func (w *TextfileWriter) WriteOnce() error {
+	start := time.Now()
+	if err := prometheus.WriteToTextfile(w.path, w.gatherer); err != nil {
+		customErr := errors.Wrap(err, errors.ErrCodeMetricsCollect, "failed to write metrics textfile")
+		customErr.WithContext("path", w.path)
+		logrus.WithFields(logrus.Fields{
+			"error_code": customErr.Code,
+			"error":      customErr.Error(),
+			"path":       w.path,
+		}).Error("Writing metrics textfile failed")
+		return customErr
+	}
+	logrus.Debugf("Metrics written to %s in %v", w.path, time.Since(start))
+	return nil
+}
+
+// Stop 停止循环写入并等待正在进行的写入结束
+func (w *TextfileWriter) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+	if w.started.Load() {
+		<-w.done
+	}
+}
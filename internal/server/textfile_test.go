@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestNewTextfileWriterValidation(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name     string
+		dir      string
+		file     string
+		interval time.Duration
+	}{
+		{name: "missing dir", dir: "", file: "tc.prom"},
+		{name: "dir does not exist", dir: filepath.Join(dir, "missing"), file: "tc.prom"},
+		{name: "wrong suffix", dir: dir, file: "tc.txt"},
+		{name: "name with path", dir: dir, file: "sub/tc.prom"},
+		{name: "negative interval", dir: dir, file: "tc.prom", interval: -time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewTextfileWriter(tt.dir, tt.file, tt.interval, nil); err == nil {
+				t.Error("NewTextfileWriter() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestTextfileWriterRun(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "tc_test_metric", Help: "test"})
+	gauge.Set(3)
+	reg.MustRegister(gauge)
+
+	dir := t.TempDir()
+	w := &TextfileWriter{
+		path:     filepath.Join(dir, "tc.prom"),
+		gatherer: reg,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	// interval 为 0 时写入一次后返回
+	if err := w.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	content, err := os.ReadFile(w.path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !strings.Contains(string(content), "tc_test_metric 3") {
+		t.Errorf("textfile content = %q, want tc_test_metric 3", content)
+	}
+	// 临时文件已被重命名，目录中只有目标文件
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("dir has %d entries, want only the textfile", len(entries))
+	}
+	w.Stop()
+}
@@ -0,0 +1,212 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"gitee.com/openeuler/uos-tc-exporter/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// certCheckInterval 握手时检查证书文件是否变化的最小间隔
+const certCheckInterval = 5 * time.Second
+
+// tlsState 一份已加载的 TLS 配置，加载成功后不再修改
+type tlsState struct {
+	config    *TLSConfig
+	options   *tlsOptions
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	// modTimes 证书、私钥和 CA 文件的修改时间，用于检测磁盘上的变化
+	modTimes [3]time.Time
+}
+
+// TLSManager 管理监听器的 TLS 配置
+//
+// 监听器在每个新连接上读取当前状态，因此启用/关闭 TLS、更换证书或 CA
+// 都不需要重新绑定端口；证书文件在握手时按 certCheckInterval 检查并自动重新加载。
+type TLSManager struct {
+	mu        sync.RWMutex
+	state     *tlsState
+	lastCheck time.Time
+}
+
+// NewTLSManager 创建 TLS 管理器，初始状态为不启用 TLS
+func NewTLSManager() *TLSManager {
+	return &TLSManager{}
+}
+
+// Load 加载并校验 TLS 配置但不生效，cfg 为空表示关闭 TLS
+func (tm *TLSManager) Load(cfg *TLSConfig) (*tlsState, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	options, err := cfg.options()
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeConfigValidation, "invalid TLS config")
+	}
+	state := &tlsState{config: cfg, options: options}
+
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeConfig, "failed to load TLS certificate").
+			WithContext("cert_file", cfg.CertFile).WithContext("key_file", cfg.KeyFile)
+	}
+	state.cert = &cert
+
+	if cfg.ClientCAFile != "" {
+		content, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeConfig, "failed to read client CA file").
+				WithContext("client_ca_file", cfg.ClientCAFile)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(content) {
+			return nil, errors.New(errors.ErrCodeConfig, "no valid certificates in client CA file").
+				WithContext("client_ca_file", cfg.ClientCAFile)
+		}
+		state.clientCAs = pool
+	}
+	state.modTimes = fileModTimes(cfg)
+	return state, nil
+}
+
+// Set 使加载好的 TLS 状态生效，对之后建立的连接有效
+func (tm *TLSManager) Set(state *tlsState) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	previous := tm.state
+	tm.state = state
+	tm.lastCheck = time.Now()
+	if state == nil {
+		if previous != nil {
+			logrus.Info("TLS disabled for HTTP listener")
+		}
+		return
+	}
+	logrus.Infof("TLS enabled for HTTP listener: %s", state.config)
+}
+
+// Apply 加载并启用 TLS 配置，失败时保持原状态
+func (tm *TLSManager) Apply(cfg *TLSConfig) error {
+	state, err := tm.Load(cfg)
+	if err != nil {
+		return err
+	}
+	tm.Set(state)
+	return nil
+}
+
+// Enabled 是否启用 TLS
+func (tm *TLSManager) Enabled() bool {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	return tm.state != nil
+}
+
+// WrapListener 包装监听器，按当前状态决定新连接是否使用 TLS
+func (tm *TLSManager) WrapListener(ln net.Listener) net.Listener {
+	return &tlsListener{Listener: ln, manager: tm}
+}
+
+// current 返回当前状态，必要时检查证书文件并重新加载
+func (tm *TLSManager) current() *tlsState {
+	tm.mu.RLock()
+	state, lastCheck := tm.state, tm.lastCheck
+	tm.mu.RUnlock()
+	if state == nil || time.Since(lastCheck) < certCheckInterval {
+		return state
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if tm.state != state {
+		// 其他连接已经完成检查
+		return tm.state
+	}
+	tm.lastCheck = time.Now()
+	if fileModTimes(state.config) == state.modTimes {
+		return state
+	}
+	reloaded, err := tm.Load(state.config)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"error_code": errors.GetErrorCode(err),
+			"error":      err.Error(),
+		}).Warn("TLS certificate files changed but reload failed, keeping previous certificate")
+		return state
+	}
+	logrus.Infof("TLS certificate reloaded from disk: %s", state.config)
+	tm.state = reloaded
+	return reloaded
+}
+
+// serverConfig 为新连接生成 tls.Config，证书通过回调获取以便握手时使用最新文件
+func (tm *TLSManager) serverConfig(state *tlsState) *tls.Config {
+	return &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			current := tm.current()
+			if current == nil {
+				current = state
+			}
+			return &tls.Config{
+				Certificates: []tls.Certificate{*current.cert},
+				ClientAuth:   current.options.clientAuth,
+				ClientCAs:    current.clientCAs,
+				MinVersion:   current.options.minVersion,
+				MaxVersion:   current.options.maxVersion,
+				CipherSuites: current.options.cipherSuites,
+			}, nil
+		},
+	}
+}
+
+// fileModTimes 读取证书相关文件的修改时间，读取失败的文件记为零值
+func fileModTimes(cfg *TLSConfig) [3]time.Time {
+	var times [3]time.Time
+	for i, path := range []string{cfg.CertFile, cfg.KeyFile, cfg.ClientCAFile} {
+		if path == "" {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil {
+			times[i] = info.ModTime()
+		}
+	}
+	return times
+}
+
+// tlsListener 按 TLSManager 的当前状态包装新连接
+type tlsListener struct {
+	net.Listener
+	manager *TLSManager
+}
+
+// Accept 实现 net.Listener 接口
+func (l *tlsListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	state := l.manager.current()
+	if state == nil {
+		return conn, nil
+	}
+	return tls.Server(conn, l.manager.serverConfig(state)), nil
+}
+
+// String 返回监听器描述
+func (l *tlsListener) String() string {
+	scheme := "http"
+	if l.manager.Enabled() {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s", scheme, l.Addr())
+}
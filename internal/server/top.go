@@ -0,0 +1,350 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gitee.com/openeuler/uos-tc-exporter/internal/metrics/collectors/business"
+	"gitee.com/openeuler/uos-tc-exporter/internal/tc"
+	"gitee.com/openeuler/uos-tc-exporter/pkg/errors"
+	"gitee.com/openeuler/uos-tc-exporter/pkg/utils"
+	"github.com/alecthomas/kingpin"
+	"github.com/dustin/go-humanize"
+	gotc "github.com/florianl/go-tc"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// top 子命令的排序方式
+const (
+	topSortDrops   = "drops"
+	topSortBacklog = "backlog"
+)
+
+// 终端控制序列
+const (
+	ansiAltScreenOn  = "\x1b[?1049h"
+	ansiAltScreenOff = "\x1b[?1049l"
+	ansiHideCursor   = "\x1b[?25l"
+	ansiShowCursor   = "\x1b[?25h"
+	ansiHome         = "\x1b[H"
+	ansiClearLine    = "\x1b[K"
+	ansiClearBelow   = "\x1b[J"
+	ansiReverse      = "\x1b[7m"
+	ansiReset        = "\x1b[0m"
+)
+
+var (
+	topNetns    *string
+	topDevice   *string
+	topSort     *string
+	topInterval *time.Duration
+)
+
+func init() {
+	top := kingpin.Command(TopCommand,
+		"Show live per-qdisc/class rates, drops, backlog and queueing delay in the terminal, without starting the server")
+	topNetns = top.Flag(
+		"netns",
+		"only show the named network namespace, default shows all namespaces").
+		String()
+	topDevice = top.Flag(
+		"device",
+		"only show this device").
+		String()
+	topSort = top.Flag(
+		"sort",
+		"initial sort order: drops (drop rate) or backlog, press d or b to switch").
+		Default(topSortDrops).
+		Enum(topSortDrops, topSortBacklog)
+	topInterval = top.Flag(
+		"interval",
+		"refresh interval").
+		Default("1s").
+		Duration()
+}
+
+// topRow 一个 qdisc 或 class 的当前速率
+type topRow struct {
+	Namespace string
+	Device    string
+	Object    string
+	Kind      string
+	Handle    string
+	Parent    string
+	Rates     business.Rates
+}
+
+// topSampler 定期读取 TC 对象，使用与业务指标相同的方法计算速率
+type topSampler struct {
+	netns  string
+	device string
+	rates  *business.RateTracker
+}
+
+func newTopSampler(netns, device string) *topSampler {
+	return &topSampler{netns: netns, device: device, rates: business.NewRateTracker(0)}
+}
+
+// sample 读取所选命名空间和设备上全部 qdisc 和 class，单个设备读取失败时跳过
+func (ts *topSampler) sample() ([]topRow, error) {
+	namespaces := []string{ts.netns}
+	if ts.netns == "" {
+		var err error
+		if namespaces, err = tc.GetNetNameSpaceList(); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeNetlinkOperation, "get net namespace list failed")
+		}
+	}
+
+	var rows []topRow
+	for _, ns := range namespaces {
+		devices, err := tc.GetInterfaceInNetNS(ns)
+		if err != nil {
+			if ts.netns != "" {
+				return nil, errors.Wrap(err, errors.ErrCodeNetlinkOperation, "get interfaces failed").
+					WithContext("namespace", ns)
+			}
+			logrus.Debugf("Get interface in netns %s failed: %v", ns, err)
+			continue
+		}
+		for _, device := range devices {
+			if device.Attributes == nil || (ts.device != "" && device.Attributes.Name != ts.device) {
+				continue
+			}
+			now := time.Now()
+			qdiscs, _ := tc.GetQdiscs(device.Index, ns)
+			classes, _ := tc.GetClasses(device.Index, ns)
+			for _, group := range []struct {
+				object string
+				objs   []gotc.Object
+			}{{"qdisc", qdiscs}, {"class", classes}} {
+				object, objs := group.object, group.objs
+				for i := range objs {
+					handle := tc.FormatHandle(objs[i].Handle)
+					rates, ok := ts.rates.Rates(business.ObjectKey(ns, device.Attributes.Name, object, handle), &objs[i], now)
+					if !ok {
+						continue
+					}
+					rows = append(rows, topRow{
+						Namespace: ns,
+						Device:    device.Attributes.Name,
+						Object:    object,
+						Kind:      objs[i].Kind,
+						Handle:    handle,
+						Parent:    tc.FormatHandle(objs[i].Parent),
+						Rates:     rates,
+					})
+				}
+			}
+		}
+	}
+	ts.rates.Prune()
+	return rows, nil
+}
+
+// sortTopRows 按丢包速率或积压字节数降序排序，相同时按累计丢包数、命名空间、设备和句柄排序
+func sortTopRows(rows []topRow, by string) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i].Rates, rows[j].Rates
+		switch by {
+		case topSortBacklog:
+			if a.Backlog != b.Backlog {
+				return a.Backlog > b.Backlog
+			}
+		default:
+			if a.DropRate != b.DropRate {
+				return a.DropRate > b.DropRate
+			}
+		}
+		if a.Drops != b.Drops {
+			return a.Drops > b.Drops
+		}
+		x, y := rows[i], rows[j]
+		if x.Namespace != y.Namespace {
+			return x.Namespace < y.Namespace
+		}
+		if x.Device != y.Device {
+			return x.Device < y.Device
+		}
+		if x.Object != y.Object {
+			return x.Object > y.Object
+		}
+		return x.Handle < y.Handle
+	})
+}
+
+// renderTop 生成一帧画面，行数和宽度超过终端大小时截断，width 或 height 为 0 表示不限制
+func renderTop(rows []topRow, by, filter string, at time.Time, width, height int) string {
+	var table bytes.Buffer
+	w := tabwriter.NewWriter(&table, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "NAMESPACE\tDEVICE\tOBJECT\tKIND\tHANDLE\tPARENT\tRATE\tPKT/S\tDROP/S\tDROPS\tBACKLOG\tDELAY\t")
+	for _, row := range rows {
+		r := row.Rates
+		rate, delay := "-", "-"
+		if r.HasThroughput {
+			rate = humanize.SIWithDigits(r.Throughput*8, 1, "bit/s")
+		}
+		if r.HasQueueDelay {
+			delay = time.Duration(r.QueueDelay * float64(time.Second)).Round(time.Microsecond).String()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%.0f\t%.0f\t%d\t%s\t%s\t\n",
+			row.Namespace, row.Device, row.Object, dashIfEmpty(row.Kind), row.Handle, dashIfEmpty(row.Parent),
+			rate, r.PacketRate, r.DropRate, r.Drops, humanize.IBytes(r.Backlog), delay)
+	}
+	w.Flush()
+
+	lines := strings.Split(strings.TrimRight(table.String(), "\n"), "\n")
+	title := fmt.Sprintf("uos_tc_exporter top - %s  objects: %d  sort: %s  filter: %s    d: sort by drops  b: sort by backlog  q: quit",
+		at.Format("15:04:05"), len(rows), by, filter)
+	var frame strings.Builder
+	writeLine := func(line, style string) {
+		if width > 0 && len(line) > width {
+			line = line[:width]
+		}
+		if style != "" {
+			line = style + line + ansiReset
+		}
+		frame.WriteString(line + ansiClearLine + "\n")
+	}
+	writeLine(title, "")
+	writeLine("", "")
+	writeLine(lines[0], ansiReverse)
+	for i, line := range lines[1:] {
+		// 标题、空行和表头占 3 行，最后一行留空避免终端滚动
+		if height > 0 && i >= height-4 {
+			break
+		}
+		writeLine(line, "")
+	}
+	frame.WriteString(ansiClearBelow)
+	return frame.String()
+}
+
+// RunTop 在终端中按 --interval 刷新各 qdisc/class 的速率，直到按 q 或收到退出信号
+func RunTop(in, out *os.File) error {
+	// 收集过程中的日志会破坏画面，只保留错误
+	logrus.SetLevel(logrus.ErrorLevel)
+
+	if *topInterval <= 0 {
+		return errors.New(errors.ErrCodeConfig, "--interval must be positive")
+	}
+	if *topNetns != "" {
+		if err := tc.ValidateNamespaceName(*topNetns); err != nil {
+			return err
+		}
+		if !tc.ValidateNamespace(*topNetns) {
+			return errors.New(errors.ErrCodeTCOperation, "network namespace not found").
+				WithContext("namespace", *topNetns)
+		}
+	}
+	restore, err := rawTerminal(in, out)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	stop := make(chan struct{})
+	go utils.HandleSignalsWith(utils.SignalHandlers{Exit: func() { close(stop) }})
+
+	keys := make(chan byte)
+	go readKeys(in, keys)
+
+	filter := "all"
+	if *topNetns != "" || *topDevice != "" {
+		filter = strings.Trim(*topNetns+"/"+*topDevice, "/")
+	}
+	sampler := newTopSampler(*topNetns, *topDevice)
+	by := *topSort
+	var rows []topRow
+	refresh := func() error {
+		var err error
+		if rows, err = sampler.sample(); err != nil {
+			return err
+		}
+		return draw(out, rows, by, filter)
+	}
+	if err := refresh(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(*topInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+			if err := refresh(); err != nil {
+				return err
+			}
+		case key := <-keys:
+			switch key {
+			case 'q', 'Q':
+				return nil
+			case 'd', 'D':
+				by = topSortDrops
+			case 'b', 'B':
+				by = topSortBacklog
+			default:
+				continue
+			}
+			if err := draw(out, rows, by, filter); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// draw 排序并输出一帧
+func draw(out *os.File, rows []topRow, by, filter string) error {
+	sortTopRows(rows, by)
+	width, height := 0, 0
+	if ws, err := unix.IoctlGetWinsize(int(out.Fd()), unix.TIOCGWINSZ); err == nil {
+		width, height = int(ws.Col), int(ws.Row)
+	}
+	_, err := io.WriteString(out, ansiHome+renderTop(rows, by, filter, time.Now(), width, height))
+	return err
+}
+
+// rawTerminal 关闭输入的行缓冲和回显并切换到备用屏幕，返回恢复终端的函数
+// 保留 ISIG，Ctrl-C 仍然产生 SIGINT
+func rawTerminal(in, out *os.File) (func(), error) {
+	if _, err := unix.IoctlGetTermios(int(out.Fd()), unix.TCGETS); err != nil {
+		return nil, errors.New(errors.ErrCodeConfig, "top requires a terminal, use the dump command for non-interactive output")
+	}
+	old, err := unix.IoctlGetTermios(int(in.Fd()), unix.TCGETS)
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeConfig, "top requires a terminal on stdin to read key presses")
+	}
+	raw := *old
+	raw.Lflag &^= unix.ICANON | unix.ECHO
+	raw.Cc[unix.VMIN], raw.Cc[unix.VTIME] = 1, 0
+	if err := unix.IoctlSetTermios(int(in.Fd()), unix.TCSETS, &raw); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeSystem, "failed to set terminal mode")
+	}
+	io.WriteString(out, ansiAltScreenOn+ansiHideCursor)
+	return func() {
+		io.WriteString(out, ansiShowCursor+ansiAltScreenOff)
+		unix.IoctlSetTermios(int(in.Fd()), unix.TCSETS, old)
+	}, nil
+}
+
+// readKeys 逐字节读取按键，读取失败时退出
+func readKeys(in io.Reader, keys chan<- byte) {
+	buf := make([]byte, 1)
+	for {
+		if _, err := in.Read(buf); err != nil {
+			return
+		}
+		keys <- buf[0]
+	}
+}
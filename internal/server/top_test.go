@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gitee.com/openeuler/uos-tc-exporter/internal/metrics/collectors/business"
+)
+
+func TestTopSortAndRender(t *testing.T) {
+	rows := []topRow{
+		{Namespace: "default", Device: "eth0", Object: "qdisc", Kind: "fq_codel", Handle: "1:0",
+			Rates: business.Rates{DropRate: 5, Drops: 100, Backlog: 10}},
+		{Namespace: "blue", Device: "eth1", Object: "class", Kind: "htb", Handle: "1:10",
+			Rates: business.Rates{DropRate: 1, Drops: 20, Backlog: 3000, Throughput: 125000, HasThroughput: true,
+				QueueDelay: 0.024, HasQueueDelay: true}},
+		{Namespace: "blue", Device: "eth1", Object: "qdisc", Kind: "htb", Handle: "1:0"},
+	}
+
+	sortTopRows(rows, topSortDrops)
+	if rows[0].Handle != "1:0" || rows[0].Device != "eth0" || rows[1].Handle != "1:10" {
+		t.Errorf("sorted by drops = %v", rows)
+	}
+	sortTopRows(rows, topSortBacklog)
+	if rows[0].Handle != "1:10" || rows[1].Device != "eth0" {
+		t.Errorf("sorted by backlog = %v", rows)
+	}
+
+	frame := renderTop(rows, topSortBacklog, "all", time.Unix(0, 0), 0, 0)
+	lines := strings.Split(strings.TrimSuffix(frame, ansiClearBelow), "\n")
+	// 标题、空行、表头和 3 行数据，最后一个换行后为空
+	if len(lines) != 7 {
+		t.Fatalf("frame has %d lines, want 7:\n%q", len(lines), frame)
+	}
+	if !strings.Contains(lines[0], "objects: 3") || !strings.Contains(lines[0], "sort: backlog") {
+		t.Errorf("title = %q", lines[0])
+	}
+	if fields := strings.Fields(strings.TrimSuffix(lines[3], ansiClearLine)); strings.Join(fields, " ") !=
+		"blue eth1 class htb 1:10 - 1 Mbit/s 0 1 20 2.9 KiB 24ms" {
+		t.Errorf("first row = %q", lines[3])
+	}
+
+	// 超出终端高度的行被截断，宽度超出的部分被裁掉
+	frame = renderTop(rows, topSortBacklog, "all", time.Unix(0, 0), 20, 5)
+	lines = strings.Split(strings.TrimSuffix(frame, ansiClearBelow), "\n")
+	if len(lines) != 5 {
+		t.Errorf("truncated frame has %d lines, want 5:\n%q", len(lines), frame)
+	}
+	if first := strings.TrimSuffix(lines[0], ansiClearLine); len(first) != 20 {
+		t.Errorf("title width = %d, want 20", len(first))
+	}
+}
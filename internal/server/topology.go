@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"gitee.com/openeuler/uos-tc-exporter/internal/tc"
+)
+
+// serveTopology 处理 /api/v1/topology?netns=&device=，以 JSON 返回 命名空间 → 设备 → qdisc → class → filter 树
+// netns 为空时返回全部命名空间，device 为空时返回全部设备
+func (hs *HttpServer) serveTopology(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	netns, device := query.Get("netns"), query.Get("device")
+	if netns != "" {
+		if err := tc.ValidateNamespaceName(netns); err != nil {
+			writeAPIError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	// 导出完整的 TC 树与抓取一样开销较大，占用抓取并发名额
+	release, ok := hs.beginScrape(NewRequest(w, r))
+	if !ok {
+		return
+	}
+	defer release()
+
+	topology, err := tc.GetTopology(netns, device)
+	switch {
+	case stderrors.Is(err, tc.ErrNamespaceNotFound), stderrors.Is(err, tc.ErrDeviceNotFound):
+		writeAPIError(w, http.StatusNotFound, err)
+	case err != nil:
+		writeAPIError(w, http.StatusInternalServerError, err)
+	default:
+		writeJSON(w, http.StatusOK, topology)
+	}
+}
@@ -0,0 +1,186 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gitee.com/openeuler/uos-tc-exporter/pkg/errors"
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/yaml.v3"
+)
+
+// WebConfig Web 配置文件，格式与 exporter-toolkit 的 web-config.yml 兼容
+type WebConfig struct {
+	TLSServerConfig *TLSConfig `yaml:"tls_server_config"`
+	// BasicAuthUsers 用户名到 bcrypt 哈希密码的映射
+	BasicAuthUsers map[string]string `yaml:"basic_auth_users"`
+	// BearerTokensFile 静态 Bearer 令牌文件，每行一个令牌，空行和 # 开头的行被忽略
+	BearerTokensFile string `yaml:"bearer_tokens_file"`
+}
+
+// TLSConfig TLS 服务端配置
+type TLSConfig struct {
+	CertFile       string   `yaml:"cert_file"`
+	KeyFile        string   `yaml:"key_file"`
+	ClientAuthType string   `yaml:"client_auth_type"`
+	ClientCAFile   string   `yaml:"client_ca_file"`
+	MinVersion     string   `yaml:"min_version"`
+	MaxVersion     string   `yaml:"max_version"`
+	CipherSuites   []string `yaml:"cipher_suites"`
+}
+
+var (
+	tlsVersions = map[string]uint16{
+		"TLS10": tls.VersionTLS10,
+		"TLS11": tls.VersionTLS11,
+		"TLS12": tls.VersionTLS12,
+		"TLS13": tls.VersionTLS13,
+	}
+	clientAuthTypes = map[string]tls.ClientAuthType{
+		"":                           tls.NoClientCert,
+		"NoClientCert":               tls.NoClientCert,
+		"RequestClientCert":          tls.RequestClientCert,
+		"RequireAnyClientCert":       tls.RequireAnyClientCert,
+		"VerifyClientCertIfGiven":    tls.VerifyClientCertIfGiven,
+		"RequireAndVerifyClientCert": tls.RequireAndVerifyClientCert,
+	}
+)
+
+// LoadWebConfig 读取 Web 配置文件，path 为空时返回空配置（不启用 TLS 和认证）
+// 文件中的相对路径以配置文件所在目录为基准
+func LoadWebConfig(path string) (*WebConfig, error) {
+	cfg := &WebConfig{}
+	if path == "" {
+		return cfg, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeConfig, "failed to read web config file").
+			WithContext("path", path)
+	}
+	if err := yaml.Unmarshal(content, cfg); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeConfig, "failed to parse web config file").
+			WithContext("path", path)
+	}
+	cfg.resolvePaths(filepath.Dir(path))
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeConfigValidation, "invalid web config file").
+			WithContext("path", path)
+	}
+	return cfg, nil
+}
+
+// resolvePaths 将相对路径转换为以 dir 为基准的路径
+func (wc *WebConfig) resolvePaths(dir string) {
+	resolve := func(path string) string {
+		if path == "" || filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(dir, path)
+	}
+	wc.BearerTokensFile = resolve(wc.BearerTokensFile)
+	if wc.TLSServerConfig != nil {
+		wc.TLSServerConfig.CertFile = resolve(wc.TLSServerConfig.CertFile)
+		wc.TLSServerConfig.KeyFile = resolve(wc.TLSServerConfig.KeyFile)
+		wc.TLSServerConfig.ClientCAFile = resolve(wc.TLSServerConfig.ClientCAFile)
+	}
+}
+
+// Validate 验证 Web 配置
+func (wc *WebConfig) Validate() error {
+	if wc.TLSServerConfig != nil {
+		if _, err := wc.TLSServerConfig.options(); err != nil {
+			return fmt.Errorf("tls_server_config: %w", err)
+		}
+	}
+	for user, hash := range wc.BasicAuthUsers {
+		if user == "" || strings.Contains(user, ":") {
+			return fmt.Errorf("basic_auth_users: invalid user name %q", user)
+		}
+		if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+			return fmt.Errorf("basic_auth_users: invalid bcrypt hash for user %q: %w", user, err)
+		}
+	}
+	return nil
+}
+
+// AuthEnabled 是否配置了任一认证方式
+func (wc *WebConfig) AuthEnabled() bool {
+	return len(wc.BasicAuthUsers) > 0 || wc.BearerTokensFile != ""
+}
+
+// tlsOptions 解析后的 TLS 参数，证书由 TLSManager 单独加载
+type tlsOptions struct {
+	clientAuth   tls.ClientAuthType
+	minVersion   uint16
+	maxVersion   uint16
+	cipherSuites []uint16
+}
+
+// options 校验并解析 TLS 参数
+func (c *TLSConfig) options() (*tlsOptions, error) {
+	if c.CertFile == "" || c.KeyFile == "" {
+		return nil, fmt.Errorf("cert_file and key_file are required")
+	}
+	opts := &tlsOptions{minVersion: tls.VersionTLS12}
+
+	clientAuth, ok := clientAuthTypes[c.ClientAuthType]
+	if !ok {
+		return nil, fmt.Errorf("invalid client_auth_type %q", c.ClientAuthType)
+	}
+	opts.clientAuth = clientAuth
+	if c.ClientCAFile == "" && (clientAuth == tls.VerifyClientCertIfGiven || clientAuth == tls.RequireAndVerifyClientCert) {
+		return nil, fmt.Errorf("client_ca_file is required for client_auth_type %s", c.ClientAuthType)
+	}
+
+	if c.MinVersion != "" {
+		version, ok := tlsVersions[c.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("invalid min_version %q, supported: TLS10, TLS11, TLS12, TLS13", c.MinVersion)
+		}
+		opts.minVersion = version
+	}
+	if c.MaxVersion != "" {
+		version, ok := tlsVersions[c.MaxVersion]
+		if !ok {
+			return nil, fmt.Errorf("invalid max_version %q, supported: TLS10, TLS11, TLS12, TLS13", c.MaxVersion)
+		}
+		if version < opts.minVersion {
+			return nil, fmt.Errorf("max_version %s is lower than min_version", c.MaxVersion)
+		}
+		opts.maxVersion = version
+	}
+
+	if len(c.CipherSuites) > 0 {
+		known := make(map[string]uint16)
+		for _, suite := range tls.CipherSuites() {
+			known[suite.Name] = suite.ID
+		}
+		for _, suite := range tls.InsecureCipherSuites() {
+			known[suite.Name] = suite.ID
+		}
+		for _, name := range c.CipherSuites {
+			id, ok := known[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown cipher suite %q", name)
+			}
+			opts.cipherSuites = append(opts.cipherSuites, id)
+		}
+	}
+	return opts, nil
+}
+
+// String 返回用于日志的简要描述
+func (c *TLSConfig) String() string {
+	parts := []string{"cert=" + c.CertFile}
+	if c.ClientAuthType != "" {
+		parts = append(parts, "client_auth="+c.ClientAuthType)
+	}
+	return strings.Join(parts, " ")
+}
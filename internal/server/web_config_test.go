@@ -0,0 +1,291 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTLSConfig_options(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  TLSConfig
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:   "defaults to TLS12",
+			config: TLSConfig{CertFile: "server.crt", KeyFile: "server.key"},
+		},
+		{
+			name:    "missing key file",
+			config:  TLSConfig{CertFile: "server.crt"},
+			wantErr: true,
+			errMsg:  "cert_file and key_file are required",
+		},
+		{
+			name: "verify client cert without CA",
+			config: TLSConfig{
+				CertFile:       "server.crt",
+				KeyFile:        "server.key",
+				ClientAuthType: "RequireAndVerifyClientCert",
+			},
+			wantErr: true,
+			errMsg:  "client_ca_file is required",
+		},
+		{
+			name: "max version lower than min version",
+			config: TLSConfig{
+				CertFile:   "server.crt",
+				KeyFile:    "server.key",
+				MinVersion: "TLS13",
+				MaxVersion: "TLS12",
+			},
+			wantErr: true,
+			errMsg:  "lower than min_version",
+		},
+		{
+			name: "unknown cipher suite",
+			config: TLSConfig{
+				CertFile:     "server.crt",
+				KeyFile:      "server.key",
+				CipherSuites: []string{"TLS_NOT_A_SUITE"},
+			},
+			wantErr: true,
+			errMsg:  "unknown cipher suite",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := tt.config.options()
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("options() error = %v, want error containing %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("options() unexpected error = %v", err)
+			}
+			if opts.minVersion != tls.VersionTLS12 {
+				t.Errorf("options() minVersion = %x, want %x", opts.minVersion, tls.VersionTLS12)
+			}
+		})
+	}
+}
+
+// testCA 测试用 CA，签发服务端和客户端证书
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+	path string
+}
+
+func newTestCA(t *testing.T, dir string) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca := &testCA{cert: cert, key: key, pool: x509.NewCertPool(), path: filepath.Join(dir, "ca.crt")}
+	ca.pool.AddCert(cert)
+	writePEM(t, ca.path, "CERTIFICATE", der)
+	return ca
+}
+
+// issue 签发序列号为 serial 的证书，写入 certPath 和 keyPath
+func (ca *testCA) issue(t *testing.T, serial int64, usage x509.ExtKeyUsage, certPath, keyPath string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "tc-exporter"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writePEM(t, certPath, "CERTIFICATE", der)
+	writePEM(t, keyPath, "EC PRIVATE KEY", keyDER)
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// startTLSServer 启动由 TLSManager 包装监听器的测试服务器
+func startTLSServer(t *testing.T, cfg *TLSConfig) (*httptest.Server, *TLSManager) {
+	t.Helper()
+	tm := NewTLSManager()
+	if err := tm.Apply(cfg); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	// 监听器自行完成 TLS 握手，httptest 按普通 HTTP 启动
+	srv.Listener = tm.WrapListener(srv.Listener)
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return srv, tm
+}
+
+// handshakeSerial 完成握手并返回服务端证书的序列号
+func handshakeSerial(t *testing.T, addr string, config *tls.Config) int64 {
+	t.Helper()
+	conn, err := tls.Dial("tcp", addr, config)
+	if err != nil {
+		t.Fatalf("tls.Dial() error = %v", err)
+	}
+	defer conn.Close()
+	return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64()
+}
+
+func TestTLSManagerHandshake(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, dir)
+	certPath, keyPath := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	ca.issue(t, 10, x509.ExtKeyUsageServerAuth, certPath, keyPath)
+
+	srv, _ := startTLSServer(t, &TLSConfig{CertFile: certPath, KeyFile: keyPath})
+	addr := srv.Listener.Addr().String()
+	if serial := handshakeSerial(t, addr, &tls.Config{RootCAs: ca.pool}); serial != 10 {
+		t.Errorf("server certificate serial = %d, want 10", serial)
+	}
+
+	// 低于 min_version 的客户端被拒绝
+	_, err := tls.Dial("tcp", addr, &tls.Config{RootCAs: ca.pool, MaxVersion: tls.VersionTLS11})
+	if err == nil {
+		t.Error("TLS 1.1 handshake succeeded, want it rejected by the TLS12 default")
+	}
+}
+
+func TestTLSManagerClientCert(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, dir)
+	certPath, keyPath := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	ca.issue(t, 10, x509.ExtKeyUsageServerAuth, certPath, keyPath)
+	clientCert, clientKey := filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+	ca.issue(t, 20, x509.ExtKeyUsageClientAuth, clientCert, clientKey)
+
+	srv, _ := startTLSServer(t, &TLSConfig{
+		CertFile:       certPath,
+		KeyFile:        keyPath,
+		ClientAuthType: "RequireAndVerifyClientCert",
+		ClientCAFile:   ca.path,
+	})
+
+	get := func(certs []tls.Certificate) error {
+		client := &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: ca.pool, Certificates: certs},
+		}}
+		defer client.CloseIdleConnections()
+		resp, err := client.Get("https://" + srv.Listener.Addr().String())
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		_, err = io.ReadAll(resp.Body)
+		return err
+	}
+
+	if err := get(nil); err == nil {
+		t.Error("request without a client certificate succeeded, want it rejected")
+	}
+	pair, err := tls.LoadX509KeyPair(clientCert, clientKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := get([]tls.Certificate{pair}); err != nil {
+		t.Errorf("request with a client certificate failed: %v", err)
+	}
+}
+
+func TestTLSManagerCertRotation(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, dir)
+	certPath, keyPath := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	ca.issue(t, 10, x509.ExtKeyUsageServerAuth, certPath, keyPath)
+
+	srv, tm := startTLSServer(t, &TLSConfig{CertFile: certPath, KeyFile: keyPath})
+	addr := srv.Listener.Addr().String()
+	clientConfig := &tls.Config{RootCAs: ca.pool}
+	if serial := handshakeSerial(t, addr, clientConfig); serial != 10 {
+		t.Fatalf("server certificate serial = %d, want 10", serial)
+	}
+
+	// 替换磁盘上的证书，修改时间设为未来以免与原文件相同
+	ca.issue(t, 11, x509.ExtKeyUsageServerAuth, certPath, keyPath)
+	future := time.Now().Add(time.Minute)
+	for _, path := range []string{certPath, keyPath} {
+		if err := os.Chtimes(path, future, future); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 检查间隔内继续使用已加载的证书
+	if serial := handshakeSerial(t, addr, clientConfig); serial != 10 {
+		t.Errorf("serial within the check interval = %d, want 10", serial)
+	}
+
+	// 模拟 certCheckInterval 已过去
+	tm.mu.Lock()
+	tm.lastCheck = time.Now().Add(-certCheckInterval)
+	tm.mu.Unlock()
+	if serial := handshakeSerial(t, addr, clientConfig); serial != 11 {
+		t.Errorf("serial after rotation = %d, want 11", serial)
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/jsimonetti/rtnetlink"
 	"github.com/sirupsen/logrus"
@@ -118,6 +120,30 @@ func GetNetworkNamespaces() ([]*NetworkNamespace, error) {
 	return namespaces, nil
 }
 
+// CountOpenNamespaceFDs 统计当前进程打开的网络命名空间文件描述符数量
+//
+// 通过 /proc/self/fd 统计指向 net:[inode] 或 NetNSDir 下文件的描述符，
+// 用于排查命名空间句柄泄漏。
+func CountOpenNamespaceFDs() (int, error) {
+	const fdDir = "/proc/self/fd"
+	entries, err := os.ReadDir(fdDir)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, entry := range entries {
+		target, err := os.Readlink(filepath.Join(fdDir, entry.Name()))
+		if err != nil {
+			// 描述符可能在遍历过程中被关闭
+			continue
+		}
+		if strings.HasPrefix(target, "net:[") || strings.HasPrefix(target, NetNSDir+"/") {
+			count++
+		}
+	}
+	return count, nil
+}
+
 // GetNetworkNamespaceNames 获取所有网络命名空间的名称列表
 //
 // 返回：
@@ -145,7 +171,12 @@ func GetNetworkNamespaceNames() ([]string, error) {
 // 返回：
 //   - []rtnetlink.LinkMessage: 网络接口列表（排除回环接口）
 //   - error: 如果获取失败则返回错误
-func GetInterfacesInNamespace(nsName string) ([]rtnetlink.LinkMessage, error) {
+func GetInterfacesInNamespace(nsName string) (interfaces []rtnetlink.LinkMessage, err error) {
+	start := time.Now()
+	defer func() {
+		observeLatency(nsName, OpLinkList, start, err)
+	}()
+
 	// 获取网络连接
 	conn, err := GetNetlinkConn(nsName)
 	if err != nil {
@@ -160,7 +191,6 @@ func GetInterfacesInNamespace(nsName string) ([]rtnetlink.LinkMessage, error) {
 	}
 
 	// 过滤掉回环接口（通常是第一个接口）
-	var interfaces []rtnetlink.LinkMessage
 	for i, link := range links {
 		// 跳过回环接口（index 1 通常是 lo）
 		if i == 0 && link.Index == 1 {
@@ -183,8 +213,23 @@ func GetInterfacesInNamespace(nsName string) ([]rtnetlink.LinkMessage, error) {
 //   - nsName: 网络命名空间名称
 //
 // 返回：
-//   - bool: 如果命名空间有效则返回 true
+//   - bool: 如果命名空间名称合法且存在则返回 true
 func ValidateNamespace(nsName string) bool {
+	if ValidateNamespaceName(nsName) != nil {
+		return false
+	}
 	ns := NewNetworkNamespace(nsName)
 	return ns.Exists()
 }
+
+// ValidateNamespaceName 验证命名空间名称只指向 NetNSDir 下的文件，
+// 拒绝空名称、"."、".." 以及包含路径分隔符或空字符的名称，防止路径穿越
+func ValidateNamespaceName(nsName string) error {
+	if nsName == "" || nsName == "." || nsName == ".." {
+		return errors.New("invalid network namespace name")
+	}
+	if strings.ContainsAny(nsName, "/\x00") {
+		return errors.New("network namespace name must not contain path separators")
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package tc
+
+import "testing"
+
+func TestValidateNamespaceName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{name: "default"},
+		{name: "ns-1"},
+		{name: "..foo"},
+		{name: "", wantErr: true},
+		{name: ".", wantErr: true},
+		{name: "..", wantErr: true},
+		{name: "../../etc/passwd", wantErr: true},
+		{name: "a/b", wantErr: true},
+		{name: "a\x00b", wantErr: true},
+	}
+	for _, tt := range tests {
+		if err := ValidateNamespaceName(tt.name); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateNamespaceName(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+	if ValidateNamespace("../..") {
+		t.Error("ValidateNamespace(\"../..\") = true, want false")
+	}
+}
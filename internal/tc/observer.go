@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+// Package tc 提供了 Linux Traffic Control (TC) 的操作接口
+package tc
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// netlink 操作名称，用于耗时观测
+const (
+	OpLinkList   = "link_list"
+	OpQdiscDump  = "qdisc_dump"
+	OpClassDump  = "class_dump"
+	OpFilterDump = "filter_dump"
+)
+
+// LatencyObserver 观测 netlink 调用耗时的回调
+type LatencyObserver func(namespace, operation string, duration time.Duration, err error)
+
+var latencyObserver atomic.Pointer[LatencyObserver]
+
+// SetLatencyObserver 设置 netlink 调用耗时观测回调，传入 nil 表示取消观测
+func SetLatencyObserver(observer LatencyObserver) {
+	if observer == nil {
+		latencyObserver.Store(nil)
+		return
+	}
+	latencyObserver.Store(&observer)
+}
+
+// observeLatency 上报一次 netlink 调用的耗时
+func observeLatency(namespace, operation string, start time.Time, err error) {
+	if observer := latencyObserver.Load(); observer != nil {
+		(*observer)(namespace, operation, time.Since(start), err)
+	}
+}
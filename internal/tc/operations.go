@@ -6,6 +6,7 @@ package tc
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/florianl/go-tc"
 	"github.com/jsimonetti/rtnetlink"
@@ -127,9 +128,15 @@ func NewTcObjectCollector(namespace string) *TcObjectCollector {
 
 // collectObjects 收集 TC 对象的通用方法
 func (tcoc *TcObjectCollector) collectObjects(
+	operation string,
 	devID uint32,
 	collectFunc func(*tc.Tc) ([]tc.Object, error),
-) ([]tc.Object, error) {
+) (result []tc.Object, err error) {
+	start := time.Now()
+	defer func() {
+		observeLatency(tcoc.connManager.namespace, operation, start, err)
+	}()
+
 	// 获取 TC 连接
 	sock, err := tcoc.connManager.GetTcConn()
 	if err != nil {
@@ -144,7 +151,6 @@ func (tcoc *TcObjectCollector) collectObjects(
 	}
 
 	// 按接口索引过滤对象
-	var result []tc.Object
 	for _, obj := range objects {
 		if obj.Ifindex == devID {
 			result = append(result, obj)
@@ -156,14 +162,14 @@ func (tcoc *TcObjectCollector) collectObjects(
 
 // GetQdiscs 获取指定接口的所有 qdisc
 func (tcoc *TcObjectCollector) GetQdiscs(devID uint32) ([]tc.Object, error) {
-	return tcoc.collectObjects(devID, func(sock *tc.Tc) ([]tc.Object, error) {
+	return tcoc.collectObjects(OpQdiscDump, devID, func(sock *tc.Tc) ([]tc.Object, error) {
 		return sock.Qdisc().Get()
 	})
 }
 
 // GetClasses 获取指定接口的所有 class
 func (tcoc *TcObjectCollector) GetClasses(devID uint32) ([]tc.Object, error) {
-	return tcoc.collectObjects(devID, func(sock *tc.Tc) ([]tc.Object, error) {
+	return tcoc.collectObjects(OpClassDump, devID, func(sock *tc.Tc) ([]tc.Object, error) {
 		return sock.Class().Get(&tc.Msg{
 			Family:  unix.AF_UNSPEC,
 			Info:    0,
@@ -175,7 +181,7 @@ func (tcoc *TcObjectCollector) GetClasses(devID uint32) ([]tc.Object, error) {
 
 // GetFilters 获取指定接口的所有 filter
 func (tcoc *TcObjectCollector) GetFilters(devID uint32) ([]tc.Object, error) {
-	return tcoc.collectObjects(devID, func(sock *tc.Tc) ([]tc.Object, error) {
+	return tcoc.collectObjects(OpFilterDump, devID, func(sock *tc.Tc) ([]tc.Object, error) {
 		return sock.Filter().Get(&tc.Msg{
 			Family:  unix.AF_UNSPEC,
 			Info:    0,
@@ -0,0 +1,276 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package tc
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+
+	"github.com/florianl/go-tc"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrNamespaceNotFound 请求的网络命名空间不存在
+	ErrNamespaceNotFound = errors.New("network namespace not found")
+	// ErrDeviceNotFound 请求的设备不存在于网络命名空间中
+	ErrDeviceNotFound = errors.New("device not found in network namespace")
+)
+
+// Topology 网络命名空间 → 设备 → qdisc → class → filter 的 TC 树
+type Topology struct {
+	Namespaces []NamespaceTopology `json:"namespaces"`
+}
+
+// NamespaceTopology 单个网络命名空间的 TC 树
+type NamespaceTopology struct {
+	Name    string           `json:"name"`
+	Devices []DeviceTopology `json:"devices"`
+	Error   string           `json:"error,omitempty"`
+}
+
+// DeviceTopology 单个设备的 TC 树，Qdiscs 只包含挂在根或 ingress 上的 qdisc
+type DeviceTopology struct {
+	Name   string          `json:"name"`
+	Index  uint32          `json:"index"`
+	Qdiscs []*TopologyNode `json:"qdiscs"`
+	// Filters 找不到所属 qdisc 或 class 的 filter
+	Filters []*FilterNode `json:"filters,omitempty"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// TopologyNode qdisc 或 class 节点，class 下可以再挂子 qdisc
+type TopologyNode struct {
+	Handle  string          `json:"handle"`
+	Parent  string          `json:"parent"`
+	Kind    string          `json:"kind"`
+	Options json.RawMessage `json:"options,omitempty"`
+	Stats   *NodeStats      `json:"stats,omitempty"`
+	XStats  json.RawMessage `json:"xstats,omitempty"`
+	Classes []*TopologyNode `json:"classes,omitempty"`
+	Qdiscs  []*TopologyNode `json:"qdiscs,omitempty"`
+	Filters []*FilterNode   `json:"filters,omitempty"`
+}
+
+// FilterNode filter 节点
+type FilterNode struct {
+	Handle   string          `json:"handle"`
+	Parent   string          `json:"parent"`
+	Kind     string          `json:"kind"`
+	Priority uint16          `json:"priority"`
+	Protocol uint16          `json:"protocol"`
+	Chain    *uint32         `json:"chain,omitempty"`
+	Options  json.RawMessage `json:"options,omitempty"`
+}
+
+// NodeStats 节点当前的统计信息，与 qdisc 收集器一致取自 Stats，requeues 取自 Stats2
+type NodeStats struct {
+	Bytes      uint64 `json:"bytes"`
+	Packets    uint32 `json:"packets"`
+	Drops      uint32 `json:"drops"`
+	Overlimits uint32 `json:"overlimits"`
+	Requeues   uint32 `json:"requeues"`
+	Qlen       uint32 `json:"qlen"`
+	Backlog    uint32 `json:"backlog"`
+	Bps        uint32 `json:"bps"`
+	Pps        uint32 `json:"pps"`
+}
+
+// GetTopology 获取 netns（为空时全部命名空间）中 device（为空时全部设备）的 TC 树
+// 单个命名空间或设备的 netlink 错误记录在对应节点的 Error 中，不中断其余部分
+func GetTopology(netns, device string) (*Topology, error) {
+	var names []string
+	if netns == "" {
+		all, err := GetNetworkNamespaceNames()
+		if err != nil {
+			return nil, err
+		}
+		names = all
+	} else {
+		if err := ValidateNamespaceName(netns); err != nil {
+			return nil, err
+		}
+		if !ValidateNamespace(netns) {
+			return nil, ErrNamespaceNotFound
+		}
+		names = []string{netns}
+	}
+
+	topology := &Topology{Namespaces: make([]NamespaceTopology, 0, len(names))}
+	for _, name := range names {
+		nsTopology := NamespaceTopology{Name: name, Devices: []DeviceTopology{}}
+		links, err := GetInterfacesInNamespace(name)
+		if err != nil {
+			logrus.Warnf("Get interfaces in netns %s failed: %v", name, err)
+			nsTopology.Error = err.Error()
+			topology.Namespaces = append(topology.Namespaces, nsTopology)
+			continue
+		}
+		for _, link := range links {
+			if link.Attributes == nil || (device != "" && link.Attributes.Name != device) {
+				continue
+			}
+			nsTopology.Devices = append(nsTopology.Devices, deviceTopology(name, link.Attributes.Name, link.Index))
+		}
+		if device != "" && netns != "" && len(nsTopology.Devices) == 0 {
+			return nil, ErrDeviceNotFound
+		}
+		topology.Namespaces = append(topology.Namespaces, nsTopology)
+	}
+	return topology, nil
+}
+
+// deviceTopology 导出单个设备的 qdisc、class 和 filter 并组装成树
+func deviceTopology(ns, name string, index uint32) DeviceTopology {
+	dev := DeviceTopology{Name: name, Index: index, Qdiscs: []*TopologyNode{}}
+	qdiscs, err := GetQdiscs(index, ns)
+	if err != nil {
+		dev.Error = err.Error()
+		return dev
+	}
+	classes, err := GetClasses(index, ns)
+	if err != nil {
+		dev.Error = err.Error()
+		return dev
+	}
+	filters, err := GetFilters(index, ns)
+	if err != nil {
+		dev.Error = err.Error()
+		return dev
+	}
+	dev.Qdiscs, dev.Filters = BuildTopology(qdiscs, classes, filters)
+	return dev
+}
+
+// BuildTopology 按父句柄将同一设备的 qdisc、class 和 filter 组装成树
+// 返回挂在根或 ingress 上（或找不到父节点）的 qdisc，以及找不到所属节点的 filter
+func BuildTopology(qdiscs, classes, filters []tc.Object) ([]*TopologyNode, []*FilterNode) {
+	qdiscNodes := make(map[uint32]*TopologyNode, len(qdiscs))
+	qdiscList := make([]*TopologyNode, len(qdiscs))
+	for i := range qdiscs {
+		node := newTopologyNode(&qdiscs[i])
+		qdiscList[i] = node
+		qdiscNodes[qdiscs[i].Handle>>16] = node
+	}
+	classNodes := make(map[uint32]*TopologyNode, len(classes))
+	classList := make([]*TopologyNode, len(classes))
+	for i := range classes {
+		node := newTopologyNode(&classes[i])
+		classList[i] = node
+		classNodes[classes[i].Handle] = node
+	}
+
+	// class 挂到父 class 下，父句柄次编号为 0 或父 class 不存在时挂到同主编号的 qdisc 下
+	for i := range classes {
+		parent := classes[i].Parent
+		if node, ok := classNodes[parent]; ok && parent&0xffff != 0 {
+			node.Classes = append(node.Classes, classList[i])
+		} else if node, ok := qdiscNodes[classes[i].Handle>>16]; ok {
+			node.Classes = append(node.Classes, classList[i])
+		}
+	}
+
+	// qdisc 挂到父 class 下，否则作为设备的顶层 qdisc
+	roots := []*TopologyNode{}
+	for i := range qdiscs {
+		if node, ok := classNodes[qdiscs[i].Parent]; ok {
+			node.Qdiscs = append(node.Qdiscs, qdiscList[i])
+			continue
+		}
+		roots = append(roots, qdiscList[i])
+	}
+
+	var orphans []*FilterNode
+	for i := range filters {
+		node := newFilterNode(&filters[i])
+		parent := filters[i].Parent
+		if owner, ok := classNodes[parent]; ok && parent&0xffff != 0 {
+			owner.Filters = append(owner.Filters, node)
+		} else if owner, ok := qdiscNodes[parent>>16]; ok {
+			owner.Filters = append(owner.Filters, node)
+		} else {
+			orphans = append(orphans, node)
+		}
+	}
+	return roots, orphans
+}
+
+func newTopologyNode(obj *tc.Object) *TopologyNode {
+	node := &TopologyNode{
+		Handle:  FormatHandle(obj.Handle),
+		Parent:  FormatHandle(obj.Parent),
+		Kind:    obj.Kind,
+		Options: objectOptions(obj),
+		Stats:   nodeStats(obj),
+	}
+	if obj.XStats != nil {
+		node.XStats = firstSetField(reflect.ValueOf(*obj.XStats), nil)
+	}
+	return node
+}
+
+func newFilterNode(obj *tc.Object) *FilterNode {
+	// filter 的 Info 高 16 位为优先级，低 16 位为网络字节序的协议号
+	info := ParseHandle(obj.Info)
+	proto := uint16(info.Minor)
+	return &FilterNode{
+		Handle:   FormatHandle(obj.Handle),
+		Parent:   FormatHandle(obj.Parent),
+		Kind:     obj.Kind,
+		Priority: uint16(info.Major),
+		Protocol: proto>>8 | proto<<8,
+		Chain:    obj.Chain,
+		Options:  objectOptions(obj),
+	}
+}
+
+func nodeStats(obj *tc.Object) *NodeStats {
+	stats := &NodeStats{}
+	switch {
+	case obj.Stats != nil:
+		s := obj.Stats
+		stats.Bytes, stats.Packets, stats.Drops, stats.Overlimits = s.Bytes, s.Packets, s.Drops, s.Overlimits
+		stats.Qlen, stats.Backlog, stats.Bps, stats.Pps = s.Qlen, s.Backlog, s.Bps, s.Pps
+	case obj.Stats2 != nil:
+		s := obj.Stats2
+		stats.Bytes, stats.Packets, stats.Drops, stats.Overlimits = s.Bytes, s.Packets, s.Drops, s.Overlimits
+		stats.Qlen, stats.Backlog = s.Qlen, s.Backlog
+	default:
+		return nil
+	}
+	if obj.Stats2 != nil {
+		stats.Requeues = obj.Stats2.Requeues
+	}
+	return stats
+}
+
+// attributeMetaFields tc.Attribute 中不属于具体 qdisc/class/filter 选项的字段
+var attributeMetaFields = map[string]bool{
+	"Kind": true, "EgressBlock": true, "IngressBlock": true, "HwOffload": true, "Chain": true,
+	"Stats": true, "XStats": true, "Stats2": true, "Stab": true, "ExtWarnMsg": true,
+}
+
+// objectOptions 返回对象已解析的选项，go-tc 每个对象只设置与其类型对应的一个选项字段
+func objectOptions(obj *tc.Object) json.RawMessage {
+	return firstSetField(reflect.ValueOf(obj.Attribute), attributeMetaFields)
+}
+
+// firstSetField 返回结构体中第一个非空指针字段的 JSON 编码
+func firstSetField(v reflect.Value, skip map[string]bool) json.RawMessage {
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if skip[t.Field(i).Name] || field.Kind() != reflect.Ptr || field.IsNil() {
+			continue
+		}
+		data, err := json.Marshal(field.Interface())
+		if err != nil {
+			logrus.Debugf("Failed to encode tc %s options: %v", t.Field(i).Name, err)
+			return nil
+		}
+		return data
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package tc
+
+import (
+	"testing"
+
+	"github.com/florianl/go-tc"
+)
+
+func TestBuildTopology(t *testing.T) {
+	object := func(kind string, handle, parent uint32) tc.Object {
+		return tc.Object{
+			Msg:       tc.Msg{Handle: handle, Parent: parent},
+			Attribute: tc.Attribute{Kind: kind},
+		}
+	}
+	qdiscs := []tc.Object{
+		object("htb", 0x10000, tc.HandleRoot),
+		object("fq_codel", 0x100000, 0x10010),
+		object("ingress", 0xffff0000, tc.HandleIngress),
+	}
+	qdiscs[0].Htb = &tc.Htb{}
+	classes := []tc.Object{
+		object("htb", 0x10001, 0x10000),
+		object("htb", 0x10010, 0x10001),
+	}
+	classes[1].Stats = &tc.Stats{Bytes: 100, Packets: 2}
+	filters := []tc.Object{
+		object("u32", 0x80000800, 0x10000),
+		object("matchall", 1, 0xfffffff2),
+		object("basic", 1, 0x50000),
+	}
+	// 优先级 10，协议 ETH_P_IP（网络字节序）
+	filters[0].Info = 10<<16 | 0x0008
+
+	roots, orphans := BuildTopology(qdiscs, classes, filters)
+	if len(roots) != 2 || roots[0].Kind != "htb" || roots[1].Kind != "ingress" {
+		t.Fatalf("roots = %+v, want htb and ingress", roots)
+	}
+	htb := roots[0]
+	if htb.Handle != "1:0" || len(htb.Options) == 0 {
+		t.Errorf("htb node = %+v, want handle 1:0 with options", htb)
+	}
+	if len(htb.Classes) != 1 || htb.Classes[0].Handle != "1:1" {
+		t.Fatalf("htb classes = %+v, want 1:1", htb.Classes)
+	}
+	leaf := htb.Classes[0].Classes
+	if len(leaf) != 1 || leaf[0].Handle != "1:16" || leaf[0].Stats == nil || leaf[0].Stats.Bytes != 100 {
+		t.Fatalf("leaf classes = %+v, want 1:16 with stats", leaf)
+	}
+	if len(leaf[0].Qdiscs) != 1 || leaf[0].Qdiscs[0].Kind != "fq_codel" {
+		t.Errorf("leaf qdiscs = %+v, want fq_codel", leaf[0].Qdiscs)
+	}
+	if len(htb.Filters) != 1 || htb.Filters[0].Priority != 10 || htb.Filters[0].Protocol != 0x0800 {
+		t.Errorf("htb filters = %+v, want u32 prio 10 protocol 0x0800", htb.Filters)
+	}
+	if len(roots[1].Filters) != 1 || roots[1].Filters[0].Kind != "matchall" {
+		t.Errorf("ingress filters = %+v, want matchall", roots[1].Filters)
+	}
+	if len(orphans) != 1 || orphans[0].Kind != "basic" {
+		t.Errorf("orphans = %+v, want basic", orphans)
+	}
+}
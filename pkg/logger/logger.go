@@ -23,6 +23,9 @@ type fileLogConfig struct {
 	level       string
 }
 
+// currentRotator 当前使用的日志文件，重新初始化时关闭
+var currentRotator *FileRotator
+
 func NewConfig(level, logPath string, maxSize int64, maxAge time.Duration) fileLogConfig {
 	return fileLogConfig{
 		level:       level,
@@ -38,6 +41,13 @@ func Init(config fileLogConfig) {
 		logrus.SetFormatter(&formatter.Formatter{})
 		logrus.SetOutput(config.FileRotator)
 	}
+	// SetOutput 持有 logrus 的锁，返回后旧文件不会再被写入
+	if config.FileRotator != nil {
+		if currentRotator != nil && currentRotator != config.FileRotator {
+			currentRotator.Close()
+		}
+		currentRotator = config.FileRotator
+	}
 	switch level := strings.ToLower(config.level); level {
 	case "debug":
 		logrus.SetLevel(logrus.DebugLevel)
@@ -0,0 +1,259 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+// Package otlp 将 Prometheus 指标编码为 OTLP ExportMetricsServiceRequest protobuf 消息
+//
+// 计数器映射为单调累计的 Sum，仪表和无类型指标映射为 Gauge，直方图和摘要映射为累计的
+// Histogram 和 Summary；指标标签映射为数据点的字符串属性，指标名称和帮助信息保持不变。
+package otlp
+
+import (
+	"math"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// aggregationTemporalityCumulative AGGREGATION_TEMPORALITY_CUMULATIVE
+const aggregationTemporalityCumulative = 2
+
+// opentelemetry.proto.* 消息的字段号
+const (
+	requestResourceMetrics = 1
+
+	resourceMetricsResource     = 1
+	resourceMetricsScopeMetrics = 2
+	resourceAttributes          = 1
+
+	scopeMetricsScope   = 1
+	scopeMetricsMetrics = 2
+	scopeName           = 1
+	scopeVersion        = 2
+
+	metricName        = 1
+	metricDescription = 2
+	metricGauge       = 5
+	metricSum         = 7
+	metricHistogram   = 9
+	metricSummary     = 11
+
+	dataPoints           = 1
+	sumTemporality       = 2
+	sumMonotonic         = 3
+	histogramTemporality = 2
+
+	numberStartTime  = 2
+	numberTime       = 3
+	numberAsDouble   = 4
+	numberAttributes = 7
+
+	histogramStartTime  = 2
+	histogramTime       = 3
+	histogramCount      = 4
+	histogramSum        = 5
+	histogramBuckets    = 6
+	histogramBounds     = 7
+	histogramAttributes = 9
+
+	summaryStartTime  = 2
+	summaryTime       = 3
+	summaryCount      = 4
+	summarySum        = 5
+	summaryQuantiles  = 6
+	summaryAttributes = 7
+	quantileQuantile  = 1
+	quantileValue     = 2
+
+	keyValueKey   = 1
+	keyValueValue = 2
+	anyValueStr   = 1
+)
+
+// KeyValue 字符串类型的资源或数据点属性
+type KeyValue struct {
+	Key   string
+	Value string
+}
+
+// Scope 产生指标的 instrumentation scope
+type Scope struct {
+	Name    string
+	Version string
+}
+
+// MarshalMetrics 将 Gatherer 的输出编码为 ExportMetricsServiceRequest
+// start 为累计指标的起始时间，now 为没有时间戳的数据点使用的时间
+func MarshalMetrics(resource []KeyValue, scope Scope, families []*dto.MetricFamily, start, now time.Time) []byte {
+	var metrics []byte
+	for _, mf := range families {
+		metrics = appendMessage(metrics, scopeMetricsMetrics, func(b []byte) []byte {
+			return appendMetric(b, mf, uint64(start.UnixNano()), uint64(now.UnixNano()))
+		})
+	}
+	return appendMessage(nil, requestResourceMetrics, func(b []byte) []byte {
+		b = appendMessage(b, resourceMetricsResource, func(b []byte) []byte {
+			return appendAttributes(b, resourceAttributes, resource)
+		})
+		return appendMessage(b, resourceMetricsScopeMetrics, func(b []byte) []byte {
+			b = appendMessage(b, scopeMetricsScope, func(b []byte) []byte {
+				b = appendString(b, scopeName, scope.Name)
+				return appendString(b, scopeVersion, scope.Version)
+			})
+			return append(b, metrics...)
+		})
+	})
+}
+
+func appendMetric(b []byte, mf *dto.MetricFamily, start, now uint64) []byte {
+	b = appendString(b, metricName, mf.GetName())
+	b = appendString(b, metricDescription, mf.GetHelp())
+	pointTime := func(m *dto.Metric) uint64 {
+		if m.TimestampMs != nil {
+			return uint64(m.GetTimestampMs()) * uint64(time.Millisecond)
+		}
+		return now
+	}
+
+	switch mf.GetType() {
+	case dto.MetricType_COUNTER:
+		return appendMessage(b, metricSum, func(b []byte) []byte {
+			for _, m := range mf.GetMetric() {
+				b = appendNumberPoint(b, m, start, pointTime(m), m.GetCounter().GetValue())
+			}
+			b = appendVarint(b, sumTemporality, aggregationTemporalityCumulative)
+			return appendVarint(b, sumMonotonic, 1)
+		})
+	case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+		return appendMessage(b, metricHistogram, func(b []byte) []byte {
+			for _, m := range mf.GetMetric() {
+				b = appendMessage(b, dataPoints, func(b []byte) []byte {
+					return appendHistogramPoint(b, m, start, pointTime(m))
+				})
+			}
+			return appendVarint(b, histogramTemporality, aggregationTemporalityCumulative)
+		})
+	case dto.MetricType_SUMMARY:
+		return appendMessage(b, metricSummary, func(b []byte) []byte {
+			for _, m := range mf.GetMetric() {
+				b = appendMessage(b, dataPoints, func(b []byte) []byte {
+					return appendSummaryPoint(b, m, start, pointTime(m))
+				})
+			}
+			return b
+		})
+	default:
+		// 仪表和无类型指标，Gauge 数据点没有起始时间
+		return appendMessage(b, metricGauge, func(b []byte) []byte {
+			for _, m := range mf.GetMetric() {
+				value := m.GetGauge().GetValue()
+				if mf.GetType() == dto.MetricType_UNTYPED {
+					value = m.GetUntyped().GetValue()
+				}
+				b = appendNumberPoint(b, m, 0, pointTime(m), value)
+			}
+			return b
+		})
+	}
+}
+
+func appendNumberPoint(b []byte, m *dto.Metric, start, ts uint64, value float64) []byte {
+	return appendMessage(b, dataPoints, func(b []byte) []byte {
+		if start != 0 {
+			b = appendFixed64(b, numberStartTime, start)
+		}
+		b = appendFixed64(b, numberTime, ts)
+		b = appendFixed64(b, numberAsDouble, math.Float64bits(value))
+		return appendAttributes(b, numberAttributes, labels(m))
+	})
+}
+
+// appendHistogramPoint 将 Prometheus 的累计桶计数转换为 OTLP 的逐桶计数，最后一个桶对应 +Inf
+func appendHistogramPoint(b []byte, m *dto.Metric, start, ts uint64) []byte {
+	h := m.GetHistogram()
+	var bounds, counts []byte
+	var previous uint64
+	for _, bucket := range h.GetBucket() {
+		if math.IsInf(bucket.GetUpperBound(), +1) {
+			continue
+		}
+		bounds = protowire.AppendFixed64(bounds, math.Float64bits(bucket.GetUpperBound()))
+		counts = protowire.AppendFixed64(counts, bucket.GetCumulativeCount()-previous)
+		previous = bucket.GetCumulativeCount()
+	}
+	counts = protowire.AppendFixed64(counts, h.GetSampleCount()-previous)
+
+	b = appendFixed64(b, histogramStartTime, start)
+	b = appendFixed64(b, histogramTime, ts)
+	b = appendFixed64(b, histogramCount, h.GetSampleCount())
+	b = appendFixed64(b, histogramSum, math.Float64bits(h.GetSampleSum()))
+	b = appendBytes(b, histogramBuckets, counts)
+	if len(bounds) > 0 {
+		b = appendBytes(b, histogramBounds, bounds)
+	}
+	return appendAttributes(b, histogramAttributes, labels(m))
+}
+
+func appendSummaryPoint(b []byte, m *dto.Metric, start, ts uint64) []byte {
+	s := m.GetSummary()
+	b = appendFixed64(b, summaryStartTime, start)
+	b = appendFixed64(b, summaryTime, ts)
+	b = appendFixed64(b, summaryCount, s.GetSampleCount())
+	b = appendFixed64(b, summarySum, math.Float64bits(s.GetSampleSum()))
+	for _, q := range s.GetQuantile() {
+		b = appendMessage(b, summaryQuantiles, func(b []byte) []byte {
+			b = appendFixed64(b, quantileQuantile, math.Float64bits(q.GetQuantile()))
+			return appendFixed64(b, quantileValue, math.Float64bits(q.GetValue()))
+		})
+	}
+	return appendAttributes(b, summaryAttributes, labels(m))
+}
+
+// labels 将指标标签（namespace、device、handle 等）转换为数据点属性
+func labels(m *dto.Metric) []KeyValue {
+	attrs := make([]KeyValue, 0, len(m.GetLabel()))
+	for _, l := range m.GetLabel() {
+		attrs = append(attrs, KeyValue{Key: l.GetName(), Value: l.GetValue()})
+	}
+	return attrs
+}
+
+func appendAttributes(b []byte, num protowire.Number, attrs []KeyValue) []byte {
+	for _, attr := range attrs {
+		b = appendMessage(b, num, func(b []byte) []byte {
+			b = appendString(b, keyValueKey, attr.Key)
+			return appendMessage(b, keyValueValue, func(b []byte) []byte {
+				return appendString(b, anyValueStr, attr.Value)
+			})
+		})
+	}
+	return b
+}
+
+// appendMessage 追加一个嵌套消息字段，消息内容由 fn 写入
+func appendMessage(b []byte, num protowire.Number, fn func([]byte) []byte) []byte {
+	return appendBytes(b, num, fn(nil))
+}
+
+func appendBytes(b []byte, num protowire.Number, v []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+func appendString(b []byte, num protowire.Number, v string) []byte {
+	if v == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
+
+func appendFixed64(b []byte, num protowire.Number, v uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, v)
+}
+
+func appendVarint(b []byte, num protowire.Number, v uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
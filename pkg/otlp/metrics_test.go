@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package otlp
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// message 解码后的 protobuf 消息，按字段号保存原始值
+type message map[protowire.Number][]any
+
+func decode(t *testing.T, b []byte) message {
+	t.Helper()
+	msg := message{}
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			t.Fatalf("invalid tag: %v", protowire.ParseError(n))
+		}
+		b = b[n:]
+		var v any
+		switch typ {
+		case protowire.BytesType:
+			v, n = protowire.ConsumeBytes(b)
+		case protowire.Fixed64Type:
+			v, n = protowire.ConsumeFixed64(b)
+		case protowire.VarintType:
+			v, n = protowire.ConsumeVarint(b)
+		default:
+			t.Fatalf("unexpected wire type %d", typ)
+		}
+		if n < 0 {
+			t.Fatalf("invalid field %d: %v", num, protowire.ParseError(n))
+		}
+		msg[num] = append(msg[num], v)
+		b = b[n:]
+	}
+	return msg
+}
+
+func (m message) sub(t *testing.T, num protowire.Number, i int) message {
+	t.Helper()
+	return decode(t, m[num][i].([]byte))
+}
+
+func (m message) str(num protowire.Number) string {
+	if len(m[num]) == 0 {
+		return ""
+	}
+	return string(m[num][0].([]byte))
+}
+
+func attributes(t *testing.T, m message, num protowire.Number) map[string]string {
+	attrs := map[string]string{}
+	for i := range m[num] {
+		kv := m.sub(t, num, i)
+		attrs[kv.str(keyValueKey)] = kv.sub(t, keyValueValue, 0).str(anyValueStr)
+	}
+	return attrs
+}
+
+func TestMarshalMetrics(t *testing.T) {
+	label := func(name, value string) *dto.LabelPair {
+		return &dto.LabelPair{Name: proto.String(name), Value: proto.String(value)}
+	}
+	families := []*dto.MetricFamily{
+		{
+			Name: proto.String("tc_qdisc_drops_total"),
+			Help: proto.String("Dropped packets."),
+			Type: dto.MetricType_COUNTER.Enum(),
+			Metric: []*dto.Metric{{
+				Label:   []*dto.LabelPair{label("namespace", "default"), label("device", "eth0"), label("handle", "1:0")},
+				Counter: &dto.Counter{Value: proto.Float64(7)},
+			}},
+		},
+		{
+			Name:   proto.String("tc_qdisc_backlog_bytes"),
+			Type:   dto.MetricType_GAUGE.Enum(),
+			Metric: []*dto.Metric{{Gauge: &dto.Gauge{Value: proto.Float64(1500)}}},
+		},
+		{
+			Name: proto.String("tc_collect_seconds"),
+			Type: dto.MetricType_HISTOGRAM.Enum(),
+			Metric: []*dto.Metric{{Histogram: &dto.Histogram{
+				SampleCount: proto.Uint64(5),
+				SampleSum:   proto.Float64(1.5),
+				Bucket: []*dto.Bucket{
+					{UpperBound: proto.Float64(0.1), CumulativeCount: proto.Uint64(2)},
+					{UpperBound: proto.Float64(1), CumulativeCount: proto.Uint64(4)},
+				},
+			}}},
+		},
+	}
+	start, now := time.Unix(100, 0), time.Unix(200, 0)
+	resource := []KeyValue{{"service.name", "uos-tc-exporter"}, {"host.name", "edge-1"}}
+	req := decode(t, MarshalMetrics(resource, Scope{Name: "tc", Version: "1.0"}, families, start, now))
+
+	rm := req.sub(t, requestResourceMetrics, 0)
+	if got := attributes(t, rm.sub(t, resourceMetricsResource, 0), resourceAttributes); got["host.name"] != "edge-1" || got["service.name"] != "uos-tc-exporter" {
+		t.Errorf("resource attributes = %v", got)
+	}
+	sm := rm.sub(t, resourceMetricsScopeMetrics, 0)
+	if scope := sm.sub(t, scopeMetricsScope, 0); scope.str(scopeName) != "tc" || scope.str(scopeVersion) != "1.0" {
+		t.Errorf("scope = %v", scope)
+	}
+	if n := len(sm[scopeMetricsMetrics]); n != 3 {
+		t.Fatalf("got %d metrics, want 3", n)
+	}
+
+	// 计数器：单调累计的 Sum，标签转换为属性
+	counter := sm.sub(t, scopeMetricsMetrics, 0)
+	if counter.str(metricName) != "tc_qdisc_drops_total" || counter.str(metricDescription) != "Dropped packets." {
+		t.Errorf("counter metric = %v", counter)
+	}
+	sum := counter.sub(t, metricSum, 0)
+	if sum[sumTemporality][0] != uint64(aggregationTemporalityCumulative) || sum[sumMonotonic][0] != uint64(1) {
+		t.Errorf("sum temporality/monotonic = %v/%v", sum[sumTemporality], sum[sumMonotonic])
+	}
+	point := sum.sub(t, dataPoints, 0)
+	if point[numberStartTime][0] != uint64(start.UnixNano()) || point[numberTime][0] != uint64(now.UnixNano()) ||
+		math.Float64frombits(point[numberAsDouble][0].(uint64)) != 7 {
+		t.Errorf("counter point = %v", point)
+	}
+	want := map[string]string{"namespace": "default", "device": "eth0", "handle": "1:0"}
+	if got := attributes(t, point, numberAttributes); !reflect.DeepEqual(got, want) {
+		t.Errorf("counter attributes = %v, want %v", got, want)
+	}
+
+	// 仪表：Gauge，没有起始时间
+	gauge := sm.sub(t, scopeMetricsMetrics, 1).sub(t, metricGauge, 0).sub(t, dataPoints, 0)
+	if _, ok := gauge[numberStartTime]; ok || math.Float64frombits(gauge[numberAsDouble][0].(uint64)) != 1500 {
+		t.Errorf("gauge point = %v", gauge)
+	}
+
+	// 直方图：累计桶计数转换为逐桶计数，最后一个为 +Inf 桶
+	hist := sm.sub(t, scopeMetricsMetrics, 2).sub(t, metricHistogram, 0).sub(t, dataPoints, 0)
+	var counts []uint64
+	for b := hist[histogramBuckets][0].([]byte); len(b) > 0; b = b[8:] {
+		v, _ := protowire.ConsumeFixed64(b)
+		counts = append(counts, v)
+	}
+	if !reflect.DeepEqual(counts, []uint64{2, 2, 1}) || hist[histogramCount][0] != uint64(5) {
+		t.Errorf("histogram bucket counts = %v, count = %v", counts, hist[histogramCount])
+	}
+}
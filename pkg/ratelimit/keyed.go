@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package ratelimit
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrRateLimitKeys 当最大键数量无效时返回此错误
+var ErrRateLimitKeys = errors.New("rate limit error: max keys must be greater than zero")
+
+// TokenBucket 是按需补充令牌的令牌桶
+//
+// 与 RateLimiter 不同，它不启动后台 goroutine，而是在每次获取时
+// 根据距上次获取的时间计算应补充的令牌，因此可以大量创建且无需 Stop。
+type TokenBucket struct {
+	mu       sync.Mutex
+	interval time.Duration
+	burst    float64
+	tokens   float64
+	last     time.Time
+}
+
+// NewTokenBucket 创建令牌桶，每个 interval 补充一个令牌，最多累积 burst 个
+func NewTokenBucket(interval time.Duration, burst int) (*TokenBucket, error) {
+	if burst <= 0 {
+		return nil, ErrRateLimitSize
+	}
+	if interval <= 0 {
+		return nil, ErrRateLimitTime
+	}
+	return &TokenBucket{
+		interval: interval,
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		last:     time.Now(),
+	}, nil
+}
+
+// Get 尝试获取一个令牌，没有可用令牌时返回 ErrRateLimited
+func (tb *TokenBucket) Get() error {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	return tb.take(time.Now())
+}
+
+// take 在 now 时刻补充令牌并尝试取出一个，调用方需持有锁
+func (tb *TokenBucket) take(now time.Time) error {
+	if elapsed := now.Sub(tb.last); elapsed > 0 {
+		tb.tokens += float64(elapsed) / float64(tb.interval)
+		if tb.tokens > tb.burst {
+			tb.tokens = tb.burst
+		}
+		tb.last = now
+	}
+	if tb.tokens < 1 {
+		return ErrRateLimited
+	}
+	tb.tokens--
+	return nil
+}
+
+// KeyedRateLimiter 为每个键（例如客户端 IP）维护独立的令牌桶
+//
+// 键的数量超过 maxKeys 时淘汰最久未访问的键，被淘汰的键再次访问时
+// 获得一个满的令牌桶。
+type KeyedRateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	burst    int
+	maxKeys  int
+	buckets  map[string]*list.Element
+	lru      *list.List
+}
+
+// keyedBucket LRU 链表中的元素
+type keyedBucket struct {
+	key    string
+	bucket *TokenBucket
+}
+
+// NewKeyedRateLimiter 创建按键限流器
+//
+// 参数：
+//   - interval: 每个键补充一个令牌的时间间隔
+//   - burst: 每个键的令牌桶容量
+//   - maxKeys: 同时记录的最大键数量
+func NewKeyedRateLimiter(interval time.Duration, burst, maxKeys int) (*KeyedRateLimiter, error) {
+	if burst <= 0 {
+		return nil, ErrRateLimitSize
+	}
+	if interval <= 0 {
+		return nil, ErrRateLimitTime
+	}
+	if maxKeys <= 0 {
+		return nil, ErrRateLimitKeys
+	}
+	return &KeyedRateLimiter{
+		interval: interval,
+		burst:    burst,
+		maxKeys:  maxKeys,
+		buckets:  make(map[string]*list.Element),
+		lru:      list.New(),
+	}, nil
+}
+
+// Get 为 key 获取一个令牌，没有可用令牌时返回 ErrRateLimited
+func (kl *KeyedRateLimiter) Get(key string) error {
+	kl.mu.Lock()
+	defer kl.mu.Unlock()
+
+	now := time.Now()
+	if elem, ok := kl.buckets[key]; ok {
+		kl.lru.MoveToFront(elem)
+		return elem.Value.(*keyedBucket).bucket.take(now)
+	}
+
+	if kl.lru.Len() >= kl.maxKeys {
+		oldest := kl.lru.Back()
+		kl.lru.Remove(oldest)
+		delete(kl.buckets, oldest.Value.(*keyedBucket).key)
+	}
+	bucket := &TokenBucket{
+		interval: kl.interval,
+		burst:    float64(kl.burst),
+		tokens:   float64(kl.burst),
+		last:     now,
+	}
+	kl.buckets[key] = kl.lru.PushFront(&keyedBucket{key: key, bucket: bucket})
+	return bucket.take(now)
+}
+
+// Len 返回当前记录的键数量
+func (kl *KeyedRateLimiter) Len() int {
+	kl.mu.Lock()
+	defer kl.mu.Unlock()
+	return kl.lru.Len()
+}
@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKeyedRateLimiter_Get(t *testing.T) {
+	limiter, err := NewKeyedRateLimiter(time.Hour, 2, 2)
+	if err != nil {
+		t.Fatalf("NewKeyedRateLimiter() error = %v", err)
+	}
+
+	// 每个键拥有独立的令牌桶
+	for i := 0; i < 2; i++ {
+		if err := limiter.Get("a"); err != nil {
+			t.Fatalf("Get(a) #%d error = %v", i, err)
+		}
+	}
+	if err := limiter.Get("a"); err != ErrRateLimited {
+		t.Fatalf("Get(a) after burst error = %v, want %v", err, ErrRateLimited)
+	}
+	if err := limiter.Get("b"); err != nil {
+		t.Fatalf("Get(b) error = %v", err)
+	}
+
+	// 超过 maxKeys 时淘汰最久未访问的键 a，a 重新获得满的令牌桶
+	if err := limiter.Get("c"); err != nil {
+		t.Fatalf("Get(c) error = %v", err)
+	}
+	if got := limiter.Len(); got != 2 {
+		t.Fatalf("Len() = %d, want 2", got)
+	}
+	if err := limiter.Get("a"); err != nil {
+		t.Fatalf("Get(a) after eviction error = %v", err)
+	}
+}
+
+func TestNewKeyedRateLimiter_InvalidArgs(t *testing.T) {
+	if _, err := NewKeyedRateLimiter(time.Second, 0, 1); err != ErrRateLimitSize {
+		t.Errorf("burst 0 error = %v, want %v", err, ErrRateLimitSize)
+	}
+	if _, err := NewKeyedRateLimiter(0, 1, 1); err != ErrRateLimitTime {
+		t.Errorf("interval 0 error = %v, want %v", err, ErrRateLimitTime)
+	}
+	if _, err := NewKeyedRateLimiter(time.Second, 1, 0); err != ErrRateLimitKeys {
+		t.Errorf("maxKeys 0 error = %v, want %v", err, ErrRateLimitKeys)
+	}
+}
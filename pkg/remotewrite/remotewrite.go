@@ -0,0 +1,161 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+// Package remotewrite 提供 Prometheus remote_write 协议的请求编码和磁盘 WAL
+//
+// 请求体为 snappy 块格式压缩的 prometheus.WriteRequest protobuf 消息，
+// 只编码时间序列的标签和样本，不发送元数据。
+package remotewrite
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/golang/snappy"
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Label 时间序列的标签
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample 样本值和毫秒时间戳
+type Sample struct {
+	Value     float64
+	Timestamp int64
+}
+
+// TimeSeries 一个时间序列，Labels 按名称排序，包含 __name__
+type TimeSeries struct {
+	Labels  []Label
+	Samples []Sample
+}
+
+// EncodeWriteRequest 将时间序列编码为压缩后的 WriteRequest 请求体
+func EncodeWriteRequest(series []TimeSeries) []byte {
+	return snappy.Encode(nil, MarshalWriteRequest(series))
+}
+
+// MarshalWriteRequest 将时间序列编码为 WriteRequest protobuf 消息
+//
+//	message WriteRequest { repeated TimeSeries timeseries = 1; }
+//	message TimeSeries   { repeated Label labels = 1; repeated Sample samples = 2; }
+//	message Label        { string name = 1; string value = 2; }
+//	message Sample       { double value = 1; int64 timestamp = 2; }
+func MarshalWriteRequest(series []TimeSeries) []byte {
+	var buf, ts, msg []byte
+	for _, s := range series {
+		ts = ts[:0]
+		for _, l := range s.Labels {
+			msg = protowire.AppendTag(msg[:0], 1, protowire.BytesType)
+			msg = protowire.AppendString(msg, l.Name)
+			msg = protowire.AppendTag(msg, 2, protowire.BytesType)
+			msg = protowire.AppendString(msg, l.Value)
+			ts = protowire.AppendTag(ts, 1, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, msg)
+		}
+		for _, sample := range s.Samples {
+			msg = protowire.AppendTag(msg[:0], 1, protowire.Fixed64Type)
+			msg = protowire.AppendFixed64(msg, math.Float64bits(sample.Value))
+			msg = protowire.AppendTag(msg, 2, protowire.VarintType)
+			msg = protowire.AppendVarint(msg, uint64(sample.Timestamp))
+			ts = protowire.AppendTag(ts, 2, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, msg)
+		}
+		buf = protowire.AppendTag(buf, 1, protowire.BytesType)
+		buf = protowire.AppendBytes(buf, ts)
+	}
+	return buf
+}
+
+// FromMetricFamilies 将 Gatherer 的输出转换为时间序列
+// 没有时间戳的指标使用 timestamp（毫秒）；external 中的标签附加到每个序列，与指标标签同名时以指标标签为准。
+// 摘要和直方图按 ExpandMetric 展开。
+func FromMetricFamilies(families []*dto.MetricFamily, timestamp int64, external []Label) []TimeSeries {
+	var series []TimeSeries
+	for _, mf := range families {
+		for _, m := range mf.GetMetric() {
+			ts := timestamp
+			if m.TimestampMs != nil {
+				ts = m.GetTimestampMs()
+			}
+			ExpandMetric(mf, m, func(name string, value float64, extra []Label) {
+				series = append(series, TimeSeries{
+					Labels:  buildLabels(name, m.GetLabel(), extra, external),
+					Samples: []Sample{{Value: value, Timestamp: ts}},
+				})
+			})
+		}
+	}
+	return series
+}
+
+// ExpandMetric 按文本格式的规则将指标族 mf 中的一个指标展开为样本，依次调用 fn
+// 摘要展开为带 quantile 标签的样本、_sum 和 _count，直方图展开为带 le 标签的 _bucket
+// （没有 +Inf 桶时补齐）、_sum 和 _count；extra 为展开产生的标签，不包含指标自身的标签
+func ExpandMetric(mf *dto.MetricFamily, m *dto.Metric, fn func(name string, value float64, extra []Label)) {
+	name := mf.GetName()
+	switch mf.GetType() {
+	case dto.MetricType_COUNTER:
+		fn(name, m.GetCounter().GetValue(), nil)
+	case dto.MetricType_GAUGE:
+		fn(name, m.GetGauge().GetValue(), nil)
+	case dto.MetricType_SUMMARY:
+		for _, q := range m.GetSummary().GetQuantile() {
+			fn(name, q.GetValue(), []Label{{"quantile", formatFloat(q.GetQuantile())}})
+		}
+		fn(name+"_sum", m.GetSummary().GetSampleSum(), nil)
+		fn(name+"_count", float64(m.GetSummary().GetSampleCount()), nil)
+	case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+		infSeen := false
+		for _, b := range m.GetHistogram().GetBucket() {
+			if math.IsInf(b.GetUpperBound(), +1) {
+				infSeen = true
+			}
+			fn(name+"_bucket", float64(b.GetCumulativeCount()), []Label{{"le", formatFloat(b.GetUpperBound())}})
+		}
+		if !infSeen {
+			fn(name+"_bucket", float64(m.GetHistogram().GetSampleCount()), []Label{{"le", "+Inf"}})
+		}
+		fn(name+"_sum", m.GetHistogram().GetSampleSum(), nil)
+		fn(name+"_count", float64(m.GetHistogram().GetSampleCount()), nil)
+	default:
+		fn(name, m.GetUntyped().GetValue(), nil)
+	}
+}
+
+// buildLabels 合并指标名、指标标签、额外标签和外部标签，按名称排序
+func buildLabels(name string, pairs []*dto.LabelPair, extra, external []Label) []Label {
+	labels := make([]Label, 0, len(pairs)+len(extra)+len(external)+1)
+	labels = append(labels, Label{"__name__", name})
+	seen := make(map[string]bool, len(pairs)+len(extra))
+	for _, p := range pairs {
+		labels = append(labels, Label{p.GetName(), p.GetValue()})
+		seen[p.GetName()] = true
+	}
+	for _, l := range extra {
+		labels = append(labels, l)
+		seen[l.Name] = true
+	}
+	for _, l := range external {
+		if !seen[l.Name] {
+			labels = append(labels, l)
+		}
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+	return labels
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, +1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package remotewrite
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/golang/snappy"
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestEncodeWriteRequest(t *testing.T) {
+	series := []TimeSeries{{
+		Labels:  []Label{{"__name__", "tc_qdisc_bytes_total"}, {"device", "eth0"}},
+		Samples: []Sample{{Value: 1, Timestamp: 1700000000000}},
+	}}
+	decoded, err := snappy.Decode(nil, EncodeWriteRequest(series))
+	if err != nil {
+		t.Fatalf("snappy decode: %v", err)
+	}
+	if !bytes.Equal(decoded, MarshalWriteRequest(series)) {
+		t.Error("decoded request body does not match the marshaled WriteRequest")
+	}
+}
+
+func TestMarshalWriteRequest(t *testing.T) {
+	series := []TimeSeries{{
+		Labels:  []Label{{"__name__", "tc_qdisc_drops_total"}, {"device", "eth0"}},
+		Samples: []Sample{{Value: 42.5, Timestamp: 1700000000123}},
+	}}
+	buf := MarshalWriteRequest(series)
+
+	// 按字段号逐层解析
+	num, typ, n := protowire.ConsumeTag(buf)
+	if num != 1 || typ != protowire.BytesType {
+		t.Fatalf("unexpected WriteRequest field %d type %d", num, typ)
+	}
+	ts, _ := protowire.ConsumeBytes(buf[n:])
+	var labels []Label
+	var samples []Sample
+	for len(ts) > 0 {
+		num, _, n := protowire.ConsumeTag(ts)
+		msg, m := protowire.ConsumeBytes(ts[n:])
+		ts = ts[n+m:]
+		fields := map[protowire.Number][]byte{}
+		var values []uint64
+		for len(msg) > 0 {
+			f, typ, n := protowire.ConsumeTag(msg)
+			msg = msg[n:]
+			switch typ {
+			case protowire.BytesType:
+				v, m := protowire.ConsumeBytes(msg)
+				fields[f], msg = v, msg[m:]
+			case protowire.Fixed64Type:
+				v, m := protowire.ConsumeFixed64(msg)
+				values, msg = append(values, v), msg[m:]
+			case protowire.VarintType:
+				v, m := protowire.ConsumeVarint(msg)
+				values, msg = append(values, v), msg[m:]
+			}
+		}
+		switch num {
+		case 1:
+			labels = append(labels, Label{string(fields[1]), string(fields[2])})
+		case 2:
+			samples = append(samples, Sample{math.Float64frombits(values[0]), int64(values[1])})
+		}
+	}
+	if !reflect.DeepEqual(labels, series[0].Labels) || !reflect.DeepEqual(samples, series[0].Samples) {
+		t.Errorf("decoded labels %v samples %v, want %v %v", labels, samples, series[0].Labels, series[0].Samples)
+	}
+}
+
+func TestFromMetricFamilies(t *testing.T) {
+	families := []*dto.MetricFamily{
+		{
+			Name: proto.String("tc_qdisc_backlog_bytes"),
+			Type: dto.MetricType_GAUGE.Enum(),
+			Metric: []*dto.Metric{{
+				Label: []*dto.LabelPair{{Name: proto.String("device"), Value: proto.String("eth0")}, {Name: proto.String("instance"), Value: proto.String("own")}},
+				Gauge: &dto.Gauge{Value: proto.Float64(3)},
+			}},
+		},
+		{
+			Name: proto.String("tc_collect_seconds"),
+			Type: dto.MetricType_HISTOGRAM.Enum(),
+			Metric: []*dto.Metric{{
+				TimestampMs: proto.Int64(5),
+				Histogram: &dto.Histogram{
+					SampleCount: proto.Uint64(2),
+					SampleSum:   proto.Float64(0.3),
+					Bucket:      []*dto.Bucket{{UpperBound: proto.Float64(0.1), CumulativeCount: proto.Uint64(1)}},
+				},
+			}},
+		},
+	}
+	series := FromMetricFamilies(families, 1000, []Label{{"instance", "edge-1"}, {"site", "lab"}})
+	if len(series) != 5 {
+		t.Fatalf("got %d series, want 5", len(series))
+	}
+	want := []Label{{"__name__", "tc_qdisc_backlog_bytes"}, {"device", "eth0"}, {"instance", "own"}, {"site", "lab"}}
+	if !reflect.DeepEqual(series[0].Labels, want) || series[0].Samples[0] != (Sample{3, 1000}) {
+		t.Errorf("gauge series = %+v", series[0])
+	}
+	inf := series[2]
+	if inf.Labels[1] != (Label{"instance", "edge-1"}) || inf.Labels[2] != (Label{"le", "+Inf"}) || inf.Samples[0] != (Sample{2, 5}) {
+		t.Errorf("+Inf bucket series = %+v", inf)
+	}
+	if series[4].Labels[0].Value != "tc_collect_seconds_count" {
+		t.Errorf("last series = %+v", series[4])
+	}
+}
@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package remotewrite
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	segmentSuffix = ".seg"
+	tmpSuffix     = ".tmp"
+)
+
+// segment WAL 中的一个段，保存一次收集编码后的请求体
+type segment struct {
+	seq  uint64
+	size int64
+}
+
+// WAL 磁盘上的环形请求队列
+//
+// 每个段是一个文件，文件名为递增的序号，先写临时文件再重命名，进程崩溃不会留下不完整的段。
+// 总大小超过上限时从最旧的段开始丢弃，保证最新的数据可以写入。
+type WAL struct {
+	dir      string
+	maxBytes int64
+
+	mu       sync.Mutex
+	segments []segment
+	bytes    int64
+	next     uint64
+}
+
+// OpenWAL 打开或创建 WAL 目录，加载已有的段并清理未完成的临时文件
+func OpenWAL(dir string, maxBytes int64) (*WAL, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create WAL directory: %w", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read WAL directory: %w", err)
+	}
+	w := &WAL{dir: dir, maxBytes: maxBytes}
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasSuffix(name, tmpSuffix) {
+			os.Remove(filepath.Join(dir, name))
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, segmentSuffix), 10, 64)
+		if err != nil || !strings.HasSuffix(name, segmentSuffix) || !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("stat WAL segment %s: %w", name, err)
+		}
+		w.segments = append(w.segments, segment{seq: seq, size: info.Size()})
+		w.bytes += info.Size()
+	}
+	sort.Slice(w.segments, func(i, j int) bool { return w.segments[i].seq < w.segments[j].seq })
+	if n := len(w.segments); n > 0 {
+		w.next = w.segments[n-1].seq + 1
+	}
+	return w, nil
+}
+
+func (w *WAL) path(seq uint64, suffix string) string {
+	return filepath.Join(w.dir, fmt.Sprintf("%020d%s", seq, suffix))
+}
+
+// Append 追加一个段，返回因超过容量上限而丢弃的旧段数
+func (w *WAL) Append(data []byte) (dropped int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	seq := w.next
+	tmp := w.path(seq, tmpSuffix)
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		os.Remove(tmp)
+		return 0, fmt.Errorf("write WAL segment: %w", err)
+	}
+	if err := os.Rename(tmp, w.path(seq, segmentSuffix)); err != nil {
+		os.Remove(tmp)
+		return 0, fmt.Errorf("commit WAL segment: %w", err)
+	}
+	w.next++
+	w.segments = append(w.segments, segment{seq: seq, size: int64(len(data))})
+	w.bytes += int64(len(data))
+
+	// 至少保留刚写入的段
+	for w.bytes > w.maxBytes && len(w.segments) > 1 {
+		if err := w.removeLocked(w.segments[0].seq); err != nil {
+			return dropped, err
+		}
+		dropped++
+	}
+	return dropped, nil
+}
+
+// Oldest 返回最旧的段，WAL 为空时 ok 为 false
+func (w *WAL) Oldest() (seq uint64, data []byte, ok bool, err error) {
+	w.mu.Lock()
+	if len(w.segments) == 0 {
+		w.mu.Unlock()
+		return 0, nil, false, nil
+	}
+	seq = w.segments[0].seq
+	w.mu.Unlock()
+
+	// 读取时不持锁，发送期间仍可追加；该段只会被 Remove 或容量淘汰删除
+	data, err = os.ReadFile(w.path(seq, segmentSuffix))
+	if err != nil {
+		return seq, nil, true, fmt.Errorf("read WAL segment %d: %w", seq, err)
+	}
+	return seq, data, true, nil
+}
+
+// Remove 删除已发送的段，段已被容量淘汰时不做任何操作
+func (w *WAL) Remove(seq uint64) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.removeLocked(seq)
+}
+
+func (w *WAL) removeLocked(seq uint64) error {
+	for i, s := range w.segments {
+		if s.seq != seq {
+			continue
+		}
+		if err := os.Remove(w.path(seq, segmentSuffix)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove WAL segment %d: %w", seq, err)
+		}
+		w.segments = append(w.segments[:i], w.segments[i+1:]...)
+		w.bytes -= s.size
+		return nil
+	}
+	return nil
+}
+
+// Len 返回待发送的段数
+func (w *WAL) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.segments)
+}
+
+// Size 返回待发送段的总字节数
+func (w *WAL) Size() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.bytes
+}
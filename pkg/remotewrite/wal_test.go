@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2025 UnionTech Software Technology Co., Ltd.
+// SPDX-License-Identifier: MIT
+
+package remotewrite
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWAL(t *testing.T) {
+	dir := t.TempDir()
+	w, err := OpenWAL(dir, 250)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if dropped, err := w.Append(bytes.Repeat([]byte{byte('a' + i)}, 100)); err != nil || dropped != 0 && i < 2 {
+			t.Fatalf("append %d: dropped=%d err=%v", i, dropped, err)
+		}
+	}
+	// 第三个段超过容量，最旧的段被丢弃
+	if w.Len() != 2 || w.Size() != 200 {
+		t.Fatalf("Len=%d Size=%d, want 2 and 200", w.Len(), w.Size())
+	}
+
+	// 未完成的临时文件在重新打开时被清理，段按序号恢复
+	os.WriteFile(filepath.Join(dir, "00000000000000000009.tmp"), []byte("partial"), 0o600)
+	w, err = OpenWAL(dir, 250)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "00000000000000000009.tmp")); !os.IsNotExist(err) {
+		t.Errorf("temporary file not removed: %v", err)
+	}
+	if _, err := w.Append([]byte("d")); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []byte
+	for {
+		seq, data, ok, err := w.Oldest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		got = append(got, data[0])
+		if err := w.Remove(seq); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if string(got) != "bcd" {
+		t.Errorf("segments read in order %q, want %q", got, "bcd")
+	}
+	if w.Len() != 0 || w.Size() != 0 {
+		t.Errorf("Len=%d Size=%d after draining", w.Len(), w.Size())
+	}
+}
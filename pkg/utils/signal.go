@@ -12,15 +12,45 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// SignalHandlers 各信号对应的处理函数，为空的处理函数对应的信号不会被监听
+type SignalHandlers struct {
+	// Exit 处理 SIGINT/SIGTERM，只会调用一次
+	Exit func()
+	// Reload 处理 SIGHUP
+	Reload func()
+	// Dump 处理 SIGUSR1
+	Dump func()
+}
+
 func HandleSignals(function func()) {
+	HandleSignalsWith(SignalHandlers{Exit: function})
+}
+
+// HandleSignalsWith 监听信号并分发给对应的处理函数，收到退出信号后返回
+func HandleSignalsWith(handlers SignalHandlers) {
 	var callback sync.Once
 	sigc := make(chan os.Signal, 1)
-	// defer close(sigc)
-	signal.Notify(sigc,
-		syscall.SIGINT,
-		syscall.SIGTERM)
+	signals := []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+	if handlers.Reload != nil {
+		signals = append(signals, syscall.SIGHUP)
+	}
+	if handlers.Dump != nil {
+		signals = append(signals, syscall.SIGUSR1)
+	}
+	signal.Notify(sigc, signals...)
 	defer signal.Stop(sigc)
-	sig := <-sigc
-	logrus.Infof("service received signal: %v", sig)
-	callback.Do(function)
+	for sig := range sigc {
+		logrus.Infof("service received signal: %v", sig)
+		switch sig {
+		case syscall.SIGHUP:
+			handlers.Reload()
+		case syscall.SIGUSR1:
+			handlers.Dump()
+		default:
+			if handlers.Exit != nil {
+				callback.Do(handlers.Exit)
+			}
+			return
+		}
+	}
 }
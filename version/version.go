@@ -3,11 +3,47 @@
 
 package version
 
-import "runtime"
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
 
+// 构建信息，Revision/Branch/BuildTime 由 Makefile 通过 -ldflags -X 注入
 var (
 	Version   = "1.0.0"
 	Revision  string
 	Branch    string
+	BuildTime string
 	GoVersion = runtime.Version()
 )
+
+// valueOrUnknown 未注入的构建信息统一显示为 unknown
+func valueOrUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
+
+// Info 返回构建信息键值对，键名与 build_info 指标的标签一致
+func Info() map[string]string {
+	return map[string]string{
+		"version":    Version,
+		"revision":   valueOrUnknown(Revision),
+		"branch":     valueOrUnknown(Branch),
+		"goversion":  GoVersion,
+		"build_time": valueOrUnknown(BuildTime),
+	}
+}
+
+// Print 返回适合 --version 输出的多行构建信息
+func Print(program string) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%s, version %s (branch: %s, revision: %s)\n",
+		program, Version, valueOrUnknown(Branch), valueOrUnknown(Revision))
+	fmt.Fprintf(&builder, "  build time: %s\n", valueOrUnknown(BuildTime))
+	fmt.Fprintf(&builder, "  go version: %s\n", GoVersion)
+	fmt.Fprintf(&builder, "  platform:   %s/%s", runtime.GOOS, runtime.GOARCH)
+	return builder.String()
+}